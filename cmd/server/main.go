@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,6 +15,10 @@ import (
 
 	stdlog "log"
 
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/candles"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/currency"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/pricing"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/scheduler"
 	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
@@ -35,10 +40,47 @@ func main() {
 		stdlog.Fatalf("failed to load config: %v", err)
 	}
 
+	registry := currency.NewISO4217Registry()
+	if cfg.Currencies.OverridesFile != "" {
+		if err := registry.LoadOverrides(cfg.Currencies.OverridesFile); err != nil {
+			stdlog.Fatalf("failed to load currency overrides: %v", err)
+		}
+	}
+	if len(cfg.Currencies.Enabled) > 0 {
+		if err := registry.SetEnabled(cfg.Currencies.Enabled); err != nil {
+			stdlog.Fatalf("invalid enabled currencies: %v", err)
+		}
+	}
+	if err := registry.Disable(cfg.Currencies.Disabled); err != nil {
+		stdlog.Fatalf("invalid disabled currencies: %v", err)
+	}
+	currency.SetDefault(registry)
+
 	memCache := cache.NewMemoryCache(time.Duration(cfg.Cache.TTL) * time.Second)
-	apiClient := external.NewClient(cfg.ExternalAPI.BaseURL, cfg.ExternalAPI.APIKey, cfg.ExternalAPI.Timeout)
+	rateProviders, err := newRateProviders(cfg, logger)
+	if err != nil {
+		stdlog.Fatalf("invalid providers config: %v", err)
+	}
 
-	conversionService := service.NewConversionService(logger, apiClient, memCache)
+	candleStore := candles.NewStore("USD", cfg.Candles.Retention)
+	pricingEngine, err := newPricingEngine(cfg)
+	if err != nil {
+		stdlog.Fatalf("invalid pricing config: %v", err)
+	}
+	feeSchedule, err := newFeeSchedule(cfg)
+	if err != nil {
+		stdlog.Fatalf("invalid fees config: %v", err)
+	}
+
+	conversionService := service.NewConversionService(logger, rateProviders, memCache,
+		service.WithCandleStore(candleStore),
+		service.WithPricing(pricingEngine),
+		service.WithFees(feeSchedule),
+		service.WithQuoteTTL(cfg.Quotes.TTL),
+		service.WithMaxBatchSize(cfg.Limits.MaxBatchSize),
+		service.WithMaxHistoryPoints(cfg.Limits.MaxHistoryPoints),
+		service.WithMaxPeriodDays(cfg.Limits.MaxPeriodDays),
+	)
 	conversionEndpoints := endpoint.MakeConversionEndpoints(conversionService)
 
 	r := chi.NewRouter()
@@ -46,6 +88,10 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
+	r.Get("/health/providers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rateProviders.Health())
+	})
 
 	httpHandler := transport.MakeHTTPHandler(conversionEndpoints, logger)
 	r.Mount("/", httpHandler)
@@ -58,7 +104,7 @@ func main() {
 	}
 
 	ctx := context.Background()
-	go scheduler.NewScheduler(conversionService, memCache).StartRateUpdater(ctx)
+	go scheduler.NewScheduler(conversionService, memCache, candleStore).StartRateUpdater(ctx)
 
 	go func() {
 		stdlog.Printf("Starting server on port %d", cfg.Server.Port)
@@ -80,3 +126,187 @@ func main() {
 	}
 	stdlog.Println("Server exited properly")
 }
+
+// defaultCallTimeout bounds a whole exchangerate_host call, retries
+// included, when call_timeout is not set.
+const defaultCallTimeout = 15 * time.Second
+
+// newRateProviders builds the failover registry from providers.sources,
+// falling back to external_api alone when none are listed. The registry
+// may try every source in turn, so their deadlines together must end
+// before server.timeout drops the connection.
+func newRateProviders(cfg *config.Config, logger kitlog.Logger) (*external.Registry, error) {
+	sources := cfg.Providers.Sources
+	if len(sources) == 0 {
+		sources = []config.ProviderConfig{{
+			Name:    "exchangerate_host",
+			BaseURL: cfg.ExternalAPI.BaseURL,
+			APIKey:  cfg.ExternalAPI.APIKey,
+			Timeout: cfg.ExternalAPI.Timeout,
+		}}
+	}
+
+	providers := make([]external.Provider, 0, len(sources))
+	var total time.Duration
+	for _, source := range sources {
+		provider, err := newRateProvider(source, logger)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+		total += provider.Timeout
+	}
+	if cfg.Server.Timeout > 0 && total >= cfg.Server.Timeout {
+		return nil, fmt.Errorf("provider timeouts add up to %s, which does not fit server.timeout %s", total, cfg.Server.Timeout)
+	}
+	return external.NewRegistry(logger, cfg.Providers.Cooldown, providers...)
+}
+
+// newRateProvider builds one configured source, and the members of a
+// consensus source in turn.
+func newRateProvider(source config.ProviderConfig, logger kitlog.Logger) (external.Provider, error) {
+	var api external.ExchangeRateAPI
+	timeout := source.Timeout
+	switch source.Type {
+	case "", "exchangerate_host":
+		// timeout bounds each attempt and call_timeout the whole call;
+		// retries that would not fit the call's deadline are skipped.
+		timeout = source.CallTimeout
+		if timeout == 0 {
+			timeout = defaultCallTimeout
+		}
+		policy, err := newRetryPolicy(source.Retry)
+		if err != nil {
+			return external.Provider{}, fmt.Errorf("provider %s: %w", source.Name, err)
+		}
+		api = external.NewClient(source.BaseURL, source.APIKey, source.Timeout,
+			external.WithRetryPolicy(policy),
+			external.WithLogger(kitlog.With(logger, "provider", source.Name)),
+		)
+	case "ecb":
+		if source.Source == "" {
+			return external.Provider{}, fmt.Errorf("provider %s: ecb needs a source", source.Name)
+		}
+		api = external.NewECBProvider(source.Source, source.HistorySource, source.Timeout)
+	case "static":
+		static, err := external.NewStaticProvider(source.Source, source.FallbackDays, source.PollInterval)
+		if err != nil {
+			return external.Provider{}, fmt.Errorf("provider %s: %w", source.Name, err)
+		}
+		api = static
+	case "consensus":
+		method, err := external.ParseConsensusMethod(source.Method)
+		if err != nil {
+			return external.Provider{}, fmt.Errorf("provider %s: %w", source.Name, err)
+		}
+		members := make([]external.Provider, 0, len(source.Members))
+		for _, member := range source.Members {
+			provider, err := newRateProvider(member, logger)
+			if err != nil {
+				return external.Provider{}, fmt.Errorf("provider %s: %w", source.Name, err)
+			}
+			members = append(members, provider)
+		}
+		api, err = external.NewConsensus(logger, external.ConsensusPolicy{
+			Method:          method,
+			TrimPercent:     source.TrimPercent,
+			MaxDeviationBps: source.MaxDeviationBps,
+			MinSources:      source.MinSources,
+		}, members...)
+		if err != nil {
+			return external.Provider{}, fmt.Errorf("provider %s: %w", source.Name, err)
+		}
+	default:
+		return external.Provider{}, fmt.Errorf("provider %s: unknown type %q", source.Name, source.Type)
+	}
+	return external.Provider{Name: source.Name, API: api, Timeout: timeout}, nil
+}
+
+// newRetryPolicy applies the fields set in rc over the default policy.
+func newRetryPolicy(rc config.RetryConfig) (external.RetryPolicy, error) {
+	policy := external.DefaultRetryPolicy()
+	if rc.MaxAttempts != 0 {
+		policy.MaxAttempts = rc.MaxAttempts
+	}
+	if rc.BaseDelay != 0 {
+		policy.BaseDelay = rc.BaseDelay
+	}
+	if rc.MaxDelay != 0 {
+		policy.MaxDelay = rc.MaxDelay
+	}
+	if rc.Jitter != nil {
+		policy.Jitter = *rc.Jitter
+	}
+	if rc.RetryableStatus != nil {
+		policy.RetryableStatus = rc.RetryableStatus
+	}
+	if rc.RetryOn != nil {
+		policy.RetryOn = make([]external.ErrorClass, 0, len(rc.RetryOn))
+		for _, name := range rc.RetryOn {
+			class, err := external.ParseErrorClass(name)
+			if err != nil {
+				return external.RetryPolicy{}, err
+			}
+			policy.RetryOn = append(policy.RetryOn, class)
+		}
+	}
+	return policy, policy.Validate()
+}
+
+func newPricingEngine(cfg *config.Config) (*pricing.Engine, error) {
+	pairs := make([]pricing.PairRule, 0, len(cfg.Pricing.Pairs))
+	for _, pair := range cfg.Pricing.Pairs {
+		rule := pricing.PairRule{From: pair.From, To: pair.To, SpreadBps: pair.SpreadBps}
+		for _, tier := range pair.Tiers {
+			minAmount, err := domain.ParseMoney(tier.MinAmount, domain.MaxScale, domain.RoundUnnecessary)
+			if err != nil {
+				return nil, fmt.Errorf("tier min_amount for %s/%s: %w", pair.From, pair.To, err)
+			}
+			rule.Tiers = append(rule.Tiers, pricing.Tier{MinAmount: minAmount, SpreadBps: tier.SpreadBps})
+		}
+		pairs = append(pairs, rule)
+	}
+	return pricing.NewEngine(cfg.Pricing.DefaultSpreadBps, cfg.Pricing.Segments, pairs)
+}
+
+func newFeeSchedule(cfg *config.Config) (*pricing.FeeSchedule, error) {
+	fees := make([]pricing.Fee, 0, len(cfg.Fees))
+	for _, fc := range cfg.Fees {
+		fee := pricing.Fee{Name: fc.Name, From: fc.From, To: fc.To, Side: pricing.Side(fc.Side)}
+		amounts := []struct {
+			field string
+			value string
+			dst   **domain.Money
+		}{
+			{"min", fc.Min, &fee.Min},
+			{"max", fc.Max, &fee.Max},
+		}
+		var err error
+		if fee.Fixed, err = parseFeeAmount(fc.Fixed); err != nil {
+			return nil, fmt.Errorf("fee %s fixed: %w", fc.Name, err)
+		}
+		if fee.Percent, err = parseFeeAmount(fc.Percent); err != nil {
+			return nil, fmt.Errorf("fee %s percent: %w", fc.Name, err)
+		}
+		for _, amount := range amounts {
+			if amount.value == "" {
+				continue
+			}
+			parsed, err := parseFeeAmount(amount.value)
+			if err != nil {
+				return nil, fmt.Errorf("fee %s %s: %w", fc.Name, amount.field, err)
+			}
+			*amount.dst = &parsed
+		}
+		fees = append(fees, fee)
+	}
+	return pricing.NewFeeSchedule(fees)
+}
+
+// parseFeeAmount parses a decimal fee setting; empty means zero.
+func parseFeeAmount(s string) (domain.Money, error) {
+	if s == "" {
+		return domain.NewMoney(0, 0), nil
+	}
+	return domain.ParseMoney(s, domain.MaxScale, domain.RoundUnnecessary)
+}
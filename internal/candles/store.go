@@ -0,0 +1,92 @@
+// Package candles records the rates observed by the scheduler and
+// aggregates them into OHLC candles.
+package candles
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// DefaultRetention is how long observations are kept when no retention is
+// configured: a month of daily candles.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// observation is one refresh of base rates, keyed like
+// domain.RateCache.BaseRates ("USD:EUR").
+type observation struct {
+	at    time.Time
+	rates *domain.RateCache
+}
+
+// Store keeps every observed set of base rates for the retention window.
+// Candles for any pair, cross pairs included, are derived at read time
+// from the same observations, so a cross candle only ever combines legs
+// that were observed together. It is safe for concurrent use.
+type Store struct {
+	mu           sync.RWMutex
+	base         string
+	retention    time.Duration
+	observations []observation
+}
+
+// NewStore returns a store for rates quoted against base. A non-positive
+// retention means DefaultRetention.
+func NewStore(base string, retention time.Duration) *Store {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Store{base: base, retention: retention}
+}
+
+// Record stores the base rates observed at the given time and drops
+// observations older than the retention window.
+func (s *Store) Record(rates map[string]domain.Money, at time.Time) {
+	if len(rates) == 0 {
+		return
+	}
+	copied := make(map[string]domain.Money, len(rates))
+	for key, rate := range rates {
+		copied[key] = rate
+	}
+	obs := observation{at: at.UTC(), rates: &domain.RateCache{BaseRates: copied}}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := sort.Search(len(s.observations), func(i int) bool {
+		return s.observations[i].at.After(obs.at)
+	})
+	s.observations = append(s.observations, observation{})
+	copy(s.observations[i+1:], s.observations[i:])
+	s.observations[i] = obs
+
+	cutoff := sort.Search(len(s.observations), func(i int) bool {
+		return !s.observations[i].at.Before(at.Add(-s.retention))
+	})
+	s.observations = s.observations[cutoff:]
+}
+
+// Candles aggregates every retained observation of from->to into candles
+// of the given resolution, oldest first.
+func (s *Store) Candles(from, to string, resolution domain.CandleResolution) []domain.Candle {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candles := make([]domain.Candle, 0)
+	width := resolution.Duration()
+	for _, obs := range s.observations {
+		rate := obs.rates.CrossRate(from, to, s.base)
+		if rate.IsZero() {
+			continue
+		}
+		start := obs.at.Truncate(width)
+		if n := len(candles); n == 0 || !candles[n-1].Start.Equal(start) {
+			candles = append(candles, domain.Candle{Start: start})
+		}
+		candles[len(candles)-1].Observe(rate)
+	}
+	return candles
+}
@@ -0,0 +1,45 @@
+package currency
+
+// Status tells whether a currency is still in circulation according to
+// ISO 4217.
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusWithdrawn Status = "withdrawn"
+)
+
+type Currency struct {
+	Code        string `json:"code" yaml:"code"`
+	NumericCode string `json:"numeric_code" yaml:"numeric_code"`
+	Name        string `json:"name" yaml:"name"`
+	Symbol      string `json:"symbol" yaml:"symbol"`
+	// MinorUnits is the ISO 4217 exponent: the number of decimal places
+	// amounts in this currency are settled at.
+	MinorUnits int `json:"minor_units" yaml:"minor_units"`
+	// Countries lists the ISO 3166-1 alpha-2 codes using the currency.
+	Countries []string `json:"countries" yaml:"countries"`
+	Status    Status   `json:"status" yaml:"status"`
+}
+
+func (c Currency) IsActive() bool {
+	return c.Status != StatusWithdrawn
+}
+
+func active(code, numeric, name, symbol string, minorUnits int, countries ...string) Currency {
+	return Currency{
+		Code:        code,
+		NumericCode: numeric,
+		Name:        name,
+		Symbol:      symbol,
+		MinorUnits:  minorUnits,
+		Countries:   countries,
+		Status:      StatusActive,
+	}
+}
+
+func withdrawn(code, numeric, name, symbol string, minorUnits int, countries ...string) Currency {
+	c := active(code, numeric, name, symbol, minorUnits, countries...)
+	c.Status = StatusWithdrawn
+	return c
+}
@@ -0,0 +1,214 @@
+package currency
+
+// DefaultEnabled is the set of codes a registry accepts until a deployment
+// configures its own.
+var DefaultEnabled = []string{"USD", "INR", "EUR", "JPY", "GBP"}
+
+// ISO4217 is the ISO 4217 list of current and historic currencies. Codes
+// whose minor unit is "N.A." in the standard (precious metals, bond market
+// units, XDR, XTS and XXX) are left out; an overrides file can add them.
+var ISO4217 = []Currency{
+	active("AED", "784", "UAE Dirham", "د.إ", 2, "AE"),
+	active("AFN", "971", "Afghani", "؋", 2, "AF"),
+	active("ALL", "008", "Lek", "L", 2, "AL"),
+	active("AMD", "051", "Armenian Dram", "֏", 2, "AM"),
+	active("AOA", "973", "Kwanza", "Kz", 2, "AO"),
+	active("ARS", "032", "Argentine Peso", "$", 2, "AR"),
+	active("AUD", "036", "Australian Dollar", "A$", 2, "AU", "CX", "CC", "HM", "KI", "NR", "NF", "TV"),
+	active("AWG", "533", "Aruban Florin", "ƒ", 2, "AW"),
+	active("AZN", "944", "Azerbaijan Manat", "₼", 2, "AZ"),
+	active("BAM", "977", "Convertible Mark", "KM", 2, "BA"),
+	active("BBD", "052", "Barbados Dollar", "Bds$", 2, "BB"),
+	active("BDT", "050", "Taka", "৳", 2, "BD"),
+	active("BHD", "048", "Bahraini Dinar", ".د.ب", 3, "BH"),
+	active("BIF", "108", "Burundi Franc", "FBu", 0, "BI"),
+	active("BMD", "060", "Bermudian Dollar", "$", 2, "BM"),
+	active("BND", "096", "Brunei Dollar", "B$", 2, "BN"),
+	active("BOB", "068", "Boliviano", "Bs.", 2, "BO"),
+	active("BOV", "984", "Mvdol", "", 2, "BO"),
+	active("BRL", "986", "Brazilian Real", "R$", 2, "BR"),
+	active("BSD", "044", "Bahamian Dollar", "B$", 2, "BS"),
+	active("BTN", "064", "Ngultrum", "Nu.", 2, "BT"),
+	active("BWP", "072", "Pula", "P", 2, "BW"),
+	active("BYN", "933", "Belarusian Ruble", "Br", 2, "BY"),
+	active("BZD", "084", "Belize Dollar", "BZ$", 2, "BZ"),
+	active("CAD", "124", "Canadian Dollar", "C$", 2, "CA"),
+	active("CDF", "976", "Congolese Franc", "FC", 2, "CD"),
+	active("CHE", "947", "WIR Euro", "", 2, "CH"),
+	active("CHF", "756", "Swiss Franc", "CHF", 2, "CH", "LI"),
+	active("CHW", "948", "WIR Franc", "", 2, "CH"),
+	active("CLF", "990", "Unidad de Fomento", "UF", 4, "CL"),
+	active("CLP", "152", "Chilean Peso", "$", 0, "CL"),
+	active("CNY", "156", "Yuan Renminbi", "¥", 2, "CN"),
+	active("COP", "170", "Colombian Peso", "$", 2, "CO"),
+	active("COU", "970", "Unidad de Valor Real", "", 2, "CO"),
+	active("CRC", "188", "Costa Rican Colon", "₡", 2, "CR"),
+	active("CUC", "931", "Peso Convertible", "CUC$", 2, "CU"),
+	active("CUP", "192", "Cuban Peso", "$", 2, "CU"),
+	active("CVE", "132", "Cabo Verde Escudo", "Esc", 2, "CV"),
+	active("CZK", "203", "Czech Koruna", "Kč", 2, "CZ"),
+	active("DJF", "262", "Djibouti Franc", "Fdj", 0, "DJ"),
+	active("DKK", "208", "Danish Krone", "kr", 2, "DK", "FO", "GL"),
+	active("DOP", "214", "Dominican Peso", "RD$", 2, "DO"),
+	active("DZD", "012", "Algerian Dinar", "د.ج", 2, "DZ"),
+	active("EGP", "818", "Egyptian Pound", "E£", 2, "EG"),
+	active("ERN", "232", "Nakfa", "Nfk", 2, "ER"),
+	active("ETB", "230", "Ethiopian Birr", "Br", 2, "ET"),
+	active("EUR", "978", "Euro", "€", 2,
+		"AD", "AT", "AX", "BE", "BG", "BL", "CY", "DE", "EE", "ES", "FI", "FR", "GF", "GP", "GR", "HR",
+		"IE", "IT", "LT", "LU", "LV", "MC", "ME", "MF", "MQ", "MT", "NL", "PM", "PT", "RE", "SI", "SK",
+		"SM", "TF", "VA", "YT"),
+	active("FJD", "242", "Fiji Dollar", "FJ$", 2, "FJ"),
+	active("FKP", "238", "Falkland Islands Pound", "£", 2, "FK"),
+	active("GBP", "826", "Pound Sterling", "£", 2, "GB", "GG", "IM", "JE"),
+	active("GEL", "981", "Lari", "₾", 2, "GE"),
+	active("GHS", "936", "Ghana Cedi", "GH₵", 2, "GH"),
+	active("GIP", "292", "Gibraltar Pound", "£", 2, "GI"),
+	active("GMD", "270", "Dalasi", "D", 2, "GM"),
+	active("GNF", "324", "Guinean Franc", "FG", 0, "GN"),
+	active("GTQ", "320", "Quetzal", "Q", 2, "GT"),
+	active("GYD", "328", "Guyana Dollar", "G$", 2, "GY"),
+	active("HKD", "344", "Hong Kong Dollar", "HK$", 2, "HK"),
+	active("HNL", "340", "Lempira", "L", 2, "HN"),
+	active("HTG", "332", "Gourde", "G", 2, "HT"),
+	active("HUF", "348", "Forint", "Ft", 2, "HU"),
+	active("IDR", "360", "Rupiah", "Rp", 2, "ID"),
+	active("ILS", "376", "New Israeli Sheqel", "₪", 2, "IL"),
+	active("INR", "356", "Indian Rupee", "₹", 2, "IN", "BT"),
+	active("IQD", "368", "Iraqi Dinar", "ع.د", 3, "IQ"),
+	active("IRR", "364", "Iranian Rial", "﷼", 2, "IR"),
+	active("ISK", "352", "Iceland Krona", "kr", 0, "IS"),
+	active("JMD", "388", "Jamaican Dollar", "J$", 2, "JM"),
+	active("JOD", "400", "Jordanian Dinar", "د.ا", 3, "JO"),
+	active("JPY", "392", "Yen", "¥", 0, "JP"),
+	active("KES", "404", "Kenyan Shilling", "KSh", 2, "KE"),
+	active("KGS", "417", "Som", "с", 2, "KG"),
+	active("KHR", "116", "Riel", "៛", 2, "KH"),
+	active("KMF", "174", "Comorian Franc", "CF", 0, "KM"),
+	active("KPW", "408", "North Korean Won", "₩", 2, "KP"),
+	active("KRW", "410", "Won", "₩", 0, "KR"),
+	active("KWD", "414", "Kuwaiti Dinar", "د.ك", 3, "KW"),
+	active("KYD", "136", "Cayman Islands Dollar", "CI$", 2, "KY"),
+	active("KZT", "398", "Tenge", "₸", 2, "KZ"),
+	active("LAK", "418", "Lao Kip", "₭", 2, "LA"),
+	active("LBP", "422", "Lebanese Pound", "ل.ل", 2, "LB"),
+	active("LKR", "144", "Sri Lanka Rupee", "Rs", 2, "LK"),
+	active("LRD", "430", "Liberian Dollar", "L$", 2, "LR"),
+	active("LSL", "426", "Loti", "L", 2, "LS"),
+	active("LYD", "434", "Libyan Dinar", "ل.د", 3, "LY"),
+	active("MAD", "504", "Moroccan Dirham", "د.م.", 2, "MA", "EH"),
+	active("MDL", "498", "Moldovan Leu", "L", 2, "MD"),
+	active("MGA", "969", "Malagasy Ariary", "Ar", 2, "MG"),
+	active("MKD", "807", "Denar", "ден", 2, "MK"),
+	active("MMK", "104", "Kyat", "K", 2, "MM"),
+	active("MNT", "496", "Tugrik", "₮", 2, "MN"),
+	active("MOP", "446", "Pataca", "MOP$", 2, "MO"),
+	active("MRU", "929", "Ouguiya", "UM", 2, "MR"),
+	active("MUR", "480", "Mauritius Rupee", "₨", 2, "MU"),
+	active("MVR", "462", "Rufiyaa", "Rf", 2, "MV"),
+	active("MWK", "454", "Malawi Kwacha", "MK", 2, "MW"),
+	active("MXN", "484", "Mexican Peso", "$", 2, "MX"),
+	active("MXV", "979", "Mexican Unidad de Inversion (UDI)", "", 2, "MX"),
+	active("MYR", "458", "Malaysian Ringgit", "RM", 2, "MY"),
+	active("MZN", "943", "Mozambique Metical", "MT", 2, "MZ"),
+	active("NAD", "516", "Namibia Dollar", "N$", 2, "NA"),
+	active("NGN", "566", "Naira", "₦", 2, "NG"),
+	active("NIO", "558", "Cordoba Oro", "C$", 2, "NI"),
+	active("NOK", "578", "Norwegian Krone", "kr", 2, "NO", "SJ", "BV"),
+	active("NPR", "524", "Nepalese Rupee", "Rs", 2, "NP"),
+	active("NZD", "554", "New Zealand Dollar", "NZ$", 2, "NZ", "CK", "NU", "PN", "TK"),
+	active("OMR", "512", "Rial Omani", "ر.ع.", 3, "OM"),
+	active("PAB", "590", "Balboa", "B/.", 2, "PA"),
+	active("PEN", "604", "Sol", "S/", 2, "PE"),
+	active("PGK", "598", "Kina", "K", 2, "PG"),
+	active("PHP", "608", "Philippine Peso", "₱", 2, "PH"),
+	active("PKR", "586", "Pakistan Rupee", "Rs", 2, "PK"),
+	active("PLN", "985", "Zloty", "zł", 2, "PL"),
+	active("PYG", "600", "Guarani", "₲", 0, "PY"),
+	active("QAR", "634", "Qatari Rial", "ر.ق", 2, "QA"),
+	active("RON", "946", "Romanian Leu", "lei", 2, "RO"),
+	active("RSD", "941", "Serbian Dinar", "дин.", 2, "RS"),
+	active("RUB", "643", "Russian Ruble", "₽", 2, "RU"),
+	active("RWF", "646", "Rwanda Franc", "FRw", 0, "RW"),
+	active("SAR", "682", "Saudi Riyal", "﷼", 2, "SA"),
+	active("SBD", "090", "Solomon Islands Dollar", "SI$", 2, "SB"),
+	active("SCR", "690", "Seychelles Rupee", "₨", 2, "SC"),
+	active("SDG", "938", "Sudanese Pound", "ج.س.", 2, "SD"),
+	active("SEK", "752", "Swedish Krona", "kr", 2, "SE"),
+	active("SGD", "702", "Singapore Dollar", "S$", 2, "SG"),
+	active("SHP", "654", "Saint Helena Pound", "£", 2, "SH"),
+	active("SLE", "925", "Leone", "Le", 2, "SL"),
+	active("SOS", "706", "Somali Shilling", "Sh", 2, "SO"),
+	active("SRD", "968", "Surinam Dollar", "$", 2, "SR"),
+	active("SSP", "728", "South Sudanese Pound", "£", 2, "SS"),
+	active("STN", "930", "Dobra", "Db", 2, "ST"),
+	active("SVC", "222", "El Salvador Colon", "₡", 2, "SV"),
+	active("SYP", "760", "Syrian Pound", "£S", 2, "SY"),
+	active("SZL", "748", "Lilangeni", "E", 2, "SZ"),
+	active("THB", "764", "Baht", "฿", 2, "TH"),
+	active("TJS", "972", "Somoni", "SM", 2, "TJ"),
+	active("TMT", "934", "Turkmenistan New Manat", "m", 2, "TM"),
+	active("TND", "788", "Tunisian Dinar", "د.ت", 3, "TN"),
+	active("TOP", "776", "Pa'anga", "T$", 2, "TO"),
+	active("TRY", "949", "Turkish Lira", "₺", 2, "TR"),
+	active("TTD", "780", "Trinidad and Tobago Dollar", "TT$", 2, "TT"),
+	active("TWD", "901", "New Taiwan Dollar", "NT$", 2, "TW"),
+	active("TZS", "834", "Tanzanian Shilling", "TSh", 2, "TZ"),
+	active("UAH", "980", "Hryvnia", "₴", 2, "UA"),
+	active("UGX", "800", "Uganda Shilling", "USh", 0, "UG"),
+	active("USD", "840", "US Dollar", "$", 2,
+		"US", "AS", "BQ", "EC", "FM", "GU", "IO", "MH", "MP", "PR", "PW", "SV", "TC", "TL", "UM", "VG", "VI"),
+	active("USN", "997", "US Dollar (Next day)", "", 2, "US"),
+	active("UYI", "940", "Uruguay Peso en Unidades Indexadas (UI)", "", 0, "UY"),
+	active("UYU", "858", "Peso Uruguayo", "$U", 2, "UY"),
+	active("UYW", "927", "Unidad Previsional", "", 4, "UY"),
+	active("UZS", "860", "Uzbekistan Sum", "soʻm", 2, "UZ"),
+	active("VED", "926", "Bolívar Soberano", "Bs.D", 2, "VE"),
+	active("VES", "928", "Bolívar Soberano", "Bs.S", 2, "VE"),
+	active("VND", "704", "Dong", "₫", 0, "VN"),
+	active("VUV", "548", "Vatu", "VT", 0, "VU"),
+	active("WST", "882", "Tala", "WS$", 2, "WS"),
+	active("XAF", "950", "CFA Franc BEAC", "FCFA", 0, "CF", "CG", "CM", "GA", "GQ", "TD"),
+	active("XCD", "951", "East Caribbean Dollar", "EC$", 2, "AG", "AI", "DM", "GD", "KN", "LC", "MS", "VC"),
+	active("XCG", "532", "Caribbean Guilder", "Cg", 2, "CW", "SX"),
+	active("XOF", "952", "CFA Franc BCEAO", "CFA", 0, "BF", "BJ", "CI", "GW", "ML", "NE", "SN", "TG"),
+	active("XPF", "953", "CFP Franc", "₣", 0, "NC", "PF", "WF"),
+	active("YER", "886", "Yemeni Rial", "﷼", 2, "YE"),
+	active("ZAR", "710", "Rand", "R", 2, "ZA", "LS", "NA"),
+	active("ZMW", "967", "Zambian Kwacha", "ZK", 2, "ZM"),
+	active("ZWG", "924", "Zimbabwe Gold", "ZiG", 2, "ZW"),
+
+	withdrawn("ANG", "532", "Netherlands Antillean Guilder", "ƒ", 2, "CW", "SX"),
+	withdrawn("ATS", "040", "Schilling", "öS", 2, "AT"),
+	withdrawn("AZM", "031", "Azerbaijanian Manat", "", 2, "AZ"),
+	withdrawn("BEF", "056", "Belgian Franc", "fr.", 0, "BE"),
+	withdrawn("BGN", "975", "Bulgarian Lev", "лв", 2, "BG"),
+	withdrawn("BYR", "974", "Belarusian Ruble", "Br", 0, "BY"),
+	withdrawn("CYP", "196", "Cyprus Pound", "£", 2, "CY"),
+	withdrawn("DEM", "276", "Deutsche Mark", "DM", 2, "DE"),
+	withdrawn("EEK", "233", "Kroon", "kr", 2, "EE"),
+	withdrawn("ESP", "724", "Spanish Peseta", "₧", 0, "ES"),
+	withdrawn("FIM", "246", "Markka", "mk", 2, "FI"),
+	withdrawn("FRF", "250", "French Franc", "F", 2, "FR"),
+	withdrawn("GHC", "288", "Cedi", "₵", 2, "GH"),
+	withdrawn("GRD", "300", "Drachma", "₯", 0, "GR"),
+	withdrawn("HRK", "191", "Kuna", "kn", 2, "HR"),
+	withdrawn("IEP", "372", "Irish Pound", "£", 2, "IE"),
+	withdrawn("ITL", "380", "Italian Lira", "₤", 0, "IT"),
+	withdrawn("LTL", "440", "Lithuanian Litas", "Lt", 2, "LT"),
+	withdrawn("LUF", "442", "Luxembourg Franc", "F", 0, "LU"),
+	withdrawn("LVL", "428", "Latvian Lats", "Ls", 2, "LV"),
+	withdrawn("MRO", "478", "Ouguiya", "UM", 2, "MR"),
+	withdrawn("MTL", "470", "Maltese Lira", "₤", 2, "MT"),
+	withdrawn("NLG", "528", "Netherlands Guilder", "ƒ", 2, "NL"),
+	withdrawn("PTE", "620", "Portuguese Escudo", "Esc", 0, "PT"),
+	withdrawn("ROL", "642", "Leu", "L", 2, "RO"),
+	withdrawn("SIT", "705", "Tolar", "SIT", 2, "SI"),
+	withdrawn("SKK", "703", "Slovak Koruna", "Sk", 2, "SK"),
+	withdrawn("SLL", "694", "Leone", "Le", 2, "SL"),
+	withdrawn("STD", "678", "Dobra", "Db", 2, "ST"),
+	withdrawn("TRL", "792", "Turkish Lira", "TL", 0, "TR"),
+	withdrawn("VEF", "937", "Bolivar", "Bs.F", 2, "VE"),
+	withdrawn("ZMK", "894", "Zambian Kwacha", "ZK", 2, "ZM"),
+	withdrawn("ZWL", "932", "Zimbabwe Dollar", "Z$", 2, "ZW"),
+}
@@ -0,0 +1,238 @@
+package currency
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxMinorUnits mirrors domain.MaxScale: a currency cannot settle at more
+// decimal places than Money can carry.
+const maxMinorUnits = 18
+
+// Registry holds the currencies a deployment knows about and which of
+// them it accepts. Lookups are O(1) and safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	byCode  map[string]Currency
+	enabled map[string]bool
+}
+
+var defaultRegistry atomic.Pointer[Registry]
+
+func init() {
+	defaultRegistry.Store(NewISO4217Registry())
+}
+
+// Default returns the process-wide registry read by domain validation.
+func Default() *Registry {
+	return defaultRegistry.Load()
+}
+
+// SetDefault replaces the process-wide registry.
+func SetDefault(r *Registry) {
+	defaultRegistry.Store(r)
+}
+
+func NewRegistry(currencies []Currency, enabled []string) *Registry {
+	r := &Registry{
+		byCode:  make(map[string]Currency, len(currencies)),
+		enabled: make(map[string]bool, len(enabled)),
+	}
+	for _, c := range currencies {
+		r.byCode[c.Code] = c
+	}
+	for _, code := range enabled {
+		if c, ok := r.byCode[normalize(code)]; ok && c.IsActive() {
+			r.enabled[c.Code] = true
+		}
+	}
+	return r
+}
+
+// NewISO4217Registry returns a registry over the full ISO 4217 table with
+// DefaultEnabled switched on.
+func NewISO4217Registry() *Registry {
+	return NewRegistry(ISO4217, DefaultEnabled)
+}
+
+func normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Lookup returns the currency for code whether or not it is enabled.
+func (r *Registry) Lookup(code string) (Currency, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.byCode[normalize(code)]
+	return c, ok
+}
+
+func (r *Registry) IsEnabled(code string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.enabled[normalize(code)]
+}
+
+// Enabled returns the enabled currencies sorted by code.
+func (r *Registry) Enabled() []Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currencies := make([]Currency, 0, len(r.enabled))
+	for code := range r.enabled {
+		currencies = append(currencies, r.byCode[code])
+	}
+	sortByCode(currencies)
+	return currencies
+}
+
+// All returns every known currency, enabled or not, sorted by code.
+func (r *Registry) All() []Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currencies := make([]Currency, 0, len(r.byCode))
+	for _, c := range r.byCode {
+		currencies = append(currencies, c)
+	}
+	sortByCode(currencies)
+	return currencies
+}
+
+// SetEnabled replaces the enabled set. The single entry "*" enables every
+// active currency. Unknown or withdrawn codes are an error and leave the
+// registry unchanged.
+func (r *Registry) SetEnabled(codes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	enabled := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if strings.TrimSpace(code) == "*" {
+			for _, c := range r.byCode {
+				if c.IsActive() {
+					enabled[c.Code] = true
+				}
+			}
+			continue
+		}
+
+		c, ok := r.byCode[normalize(code)]
+		if !ok {
+			return fmt.Errorf("unknown currency: %s", code)
+		}
+		if !c.IsActive() {
+			return fmt.Errorf("currency %s is withdrawn and cannot be enabled", c.Code)
+		}
+		enabled[c.Code] = true
+	}
+
+	r.enabled = enabled
+	return nil
+}
+
+// Disable switches off the given codes.
+func (r *Registry) Disable(codes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range codes {
+		normalized := normalize(code)
+		if _, ok := r.byCode[normalized]; !ok {
+			return fmt.Errorf("unknown currency: %s", code)
+		}
+		delete(r.enabled, normalized)
+	}
+	return nil
+}
+
+// override is one entry of an overrides file. Unset fields keep the value
+// from the built-in table, so a file only has to name what it changes.
+type override struct {
+	Code        string   `yaml:"code"`
+	NumericCode *string  `yaml:"numeric_code"`
+	Name        *string  `yaml:"name"`
+	Symbol      *string  `yaml:"symbol"`
+	MinorUnits  *int     `yaml:"minor_units"`
+	Countries   []string `yaml:"countries"`
+	Status      *Status  `yaml:"status"`
+}
+
+// LoadOverrides merges a YAML or JSON list of currency entries into the
+// registry. Entries for unknown codes add new currencies; withdrawing an
+// enabled currency also disables it.
+func (r *Registry) LoadOverrides(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var overrides []override
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("invalid currency overrides in %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	merged := make(map[string]Currency, len(overrides))
+	for _, o := range overrides {
+		code := normalize(o.Code)
+		if len(code) != 3 {
+			return fmt.Errorf("invalid currency code in %s: %q", path, o.Code)
+		}
+
+		c, ok := merged[code]
+		if !ok {
+			c, ok = r.byCode[code]
+		}
+		if !ok {
+			c = Currency{Code: code, Status: StatusActive}
+		}
+		if o.NumericCode != nil {
+			c.NumericCode = *o.NumericCode
+		}
+		if o.Name != nil {
+			c.Name = *o.Name
+		}
+		if o.Symbol != nil {
+			c.Symbol = *o.Symbol
+		}
+		if o.MinorUnits != nil {
+			if *o.MinorUnits < 0 || *o.MinorUnits > maxMinorUnits {
+				return fmt.Errorf("invalid minor units for %s in %s: %d", code, path, *o.MinorUnits)
+			}
+			c.MinorUnits = *o.MinorUnits
+		}
+		if o.Countries != nil {
+			c.Countries = o.Countries
+		}
+		if o.Status != nil {
+			if *o.Status != StatusActive && *o.Status != StatusWithdrawn {
+				return fmt.Errorf("invalid status for %s in %s: %s", code, path, *o.Status)
+			}
+			c.Status = *o.Status
+		}
+		merged[code] = c
+	}
+
+	for code, c := range merged {
+		r.byCode[code] = c
+		if !c.IsActive() {
+			delete(r.enabled, code)
+		}
+	}
+	return nil
+}
+
+func sortByCode(currencies []Currency) {
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+}
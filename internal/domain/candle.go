@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CandleResolution is the width of one OHLC candle.
+type CandleResolution string
+
+const (
+	Resolution5m CandleResolution = "5m"
+	Resolution1h CandleResolution = "1h"
+	Resolution1d CandleResolution = "1d"
+)
+
+// CandleResolutions lists every supported resolution, finest first.
+var CandleResolutions = []CandleResolution{Resolution5m, Resolution1h, Resolution1d}
+
+// ParseCandleResolution parses a resolution name; the empty string means
+// Resolution1h.
+func ParseCandleResolution(s string) (CandleResolution, error) {
+	resolution := CandleResolution(strings.ToLower(strings.TrimSpace(s)))
+	if resolution == "" {
+		return Resolution1h, nil
+	}
+	for _, r := range CandleResolutions {
+		if r == resolution {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("unknown resolution %q (want 5m, 1h or 1d)", s)
+}
+
+// Duration returns the span of one candle. Daily candles follow UTC days.
+func (r CandleResolution) Duration() time.Duration {
+	switch r {
+	case Resolution5m:
+		return 5 * time.Minute
+	case Resolution1d:
+		return 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+// Candle aggregates the rates observed in [Start, Start+resolution).
+type Candle struct {
+	Start        time.Time `json:"start"`
+	Open         Money     `json:"open"`
+	High         Money     `json:"high"`
+	Low          Money     `json:"low"`
+	Close        Money     `json:"close"`
+	Observations int       `json:"observations"`
+}
+
+// Observe folds one rate into the candle; rates must arrive in time order.
+func (c *Candle) Observe(rate Money) {
+	if c.Observations == 0 {
+		c.Open, c.High, c.Low = rate, rate, rate
+	}
+	if rate.Cmp(c.High) > 0 {
+		c.High = rate
+	}
+	if rate.Cmp(c.Low) < 0 {
+		c.Low = rate
+	}
+	c.Close = rate
+	c.Observations++
+}
+
+type CandleRequest struct {
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Resolution CandleResolution `json:"resolution"`
+}
+
+// CandleResponse lists the pair's candles oldest first. Periods without
+// any observation have no candle.
+type CandleResponse struct {
+	Success    bool             `json:"success"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Resolution CandleResolution `json:"resolution"`
+	Candles    []Candle         `json:"candles"`
+}
@@ -1,41 +1,286 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 )
 
+// MaxHistoricalDays is how far back historical rates can be requested.
+const MaxHistoricalDays = 90
+
+// MaxSlippageBps is the widest tolerance a request may ask for: 100%.
+const MaxSlippageBps = 10000
+
+// FixedSide says which side of a conversion Amount is given for.
+type FixedSide string
+
+const (
+	// FixedFrom converts Amount of From into To; it is the default.
+	FixedFrom FixedSide = "from"
+	// FixedTo treats Amount as what must be received in To and computes
+	// the From amount to charge for it.
+	FixedTo FixedSide = "to"
+)
+
+// ParseFixedSide parses a side name; the empty string means FixedFrom.
+func ParseFixedSide(s string) (FixedSide, error) {
+	switch side := FixedSide(strings.ToLower(strings.TrimSpace(s))); side {
+	case "":
+		return FixedFrom, nil
+	case FixedFrom, FixedTo:
+		return side, nil
+	default:
+		return "", fmt.Errorf("unknown fixed_side %q (want from or to)", s)
+	}
+}
+
+// ConversionRequest converts Amount from one currency to another. When
+// ExpectedRate is set, the conversion only goes ahead if the rate it would
+// use is within MaxSlippageBps basis points of it (exactly equal when
+// MaxSlippageBps is zero).
 type ConversionRequest struct {
-	From   string    `json:"from"`
-	To     string    `json:"to"`
-	Amount Money     `json:"amount"`
-	Date   time.Time `json:"date,omitempty"`
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	Amount         Money        `json:"amount"`
+	Date           time.Time    `json:"date,omitempty"`
+	Rounding       RoundingMode `json:"rounding,omitempty"`
+	ExpectedRate   *Money       `json:"expected_rate,omitempty"`
+	MaxSlippageBps int          `json:"max_slippage_bps,omitempty"`
+	FixedSide      FixedSide    `json:"fixed_side,omitempty"`
+	Segment        string       `json:"segment,omitempty"`
 }
 
+// ConversionResponse carries Result rounded to the result currency's ISO
+// 4217 minor units and UnroundedResult at full precision. Rate is the
+// customer rate the conversion used: MidRate less SpreadBps. Margin is
+// what the spread and rounding earned, in MarginCurrency. RateDate is the
+// day the provider priced, which is earlier than the requested date when
+// it fell back over a weekend or holiday; Historical is set when the rate
+// came from the historical source rather than live rates.
+//
+// Fees itemises every fee charged. GrossAmount is the converted amount
+// and NetAmount what the recipient gets after target-currency fees, both
+// in To; TotalCharged is the source amount plus source-currency fees.
+//
+// With FixedSide "to", Result is the amount of From to charge, rounded up
+// so the target is always covered, and RoundTripAmount is what that charge
+// actually converts to.
 type ConversionResponse struct {
-	Success bool  `json:"success"`
-	Result  Money `json:"result"`
-	Rate    Money `json:"rate"`
+	Success         bool         `json:"success"`
+	Result          Money        `json:"result"`
+	UnroundedResult Money        `json:"unrounded_result"`
+	Rate            Money        `json:"rate"`
+	MidRate         Money        `json:"mid_rate"`
+	SpreadBps       int          `json:"spread_bps"`
+	Margin          Money        `json:"margin"`
+	MarginCurrency  string       `json:"margin_currency"`
+	Fees            []FeeItem    `json:"fees"`
+	GrossAmount     Money        `json:"gross_amount"`
+	NetAmount       Money        `json:"net_amount"`
+	TotalCharged    Money        `json:"total_charged"`
+	RateDate        string       `json:"rate_date"`
+	Historical      bool         `json:"historical"`
+	Rounding        RoundingMode `json:"rounding"`
+	FixedSide       FixedSide    `json:"fixed_side"`
+	RoundTripAmount *Money       `json:"round_trip_amount,omitempty"`
+	Sources         []string     `json:"sources,omitempty"`
 }
 
-func (r *ConversionRequest) Validate() error {
-	if r.From == "" {
-		return fmt.Errorf("from currency is required")
+// BatchConversionItem is the outcome of one request in a batch: exactly
+// one of Result and Error is set.
+type BatchConversionItem struct {
+	Index  int                 `json:"index"`
+	Result *ConversionResponse `json:"result,omitempty"`
+	Error  *Error              `json:"error,omitempty"`
+}
+
+// BatchConversionResponse reports every item of a batch in request order.
+// Success is only set when no item failed.
+type BatchConversionResponse struct {
+	Success   bool                  `json:"success"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Items     []BatchConversionItem `json:"items"`
+}
+
+// MultiConversionRequest converts one amount into several currencies.
+type MultiConversionRequest struct {
+	From     string       `json:"from"`
+	To       []string     `json:"to"`
+	Amount   Money        `json:"amount"`
+	Rounding RoundingMode `json:"rounding,omitempty"`
+	Segment  string       `json:"segment,omitempty"`
+}
+
+// MultiConversionResult is priced like a ConversionResponse: Rate is the
+// customer rate, MidRate the rate before the spread.
+type MultiConversionResult struct {
+	To              string `json:"to"`
+	Result          Money  `json:"result"`
+	UnroundedResult Money  `json:"unrounded_result"`
+	Rate            Money  `json:"rate"`
+	MidRate         Money  `json:"mid_rate"`
+	SpreadBps       int    `json:"spread_bps"`
+}
+
+// MultiConversionResponse prices every target from the same rate snapshot,
+// taken at RatesAsOf.
+type MultiConversionResponse struct {
+	Success   bool                    `json:"success"`
+	From      string                  `json:"from"`
+	Amount    Money                   `json:"amount"`
+	Base      string                  `json:"base"`
+	RatesAsOf time.Time               `json:"rates_as_of"`
+	Rounding  RoundingMode            `json:"rounding"`
+	Results   []MultiConversionResult `json:"results"`
+}
+
+// Validate normalizes every code and drops duplicate targets.
+func (r *MultiConversionRequest) Validate() error {
+	from, err := NormalizeCurrency("from", r.From)
+	if err != nil {
+		return err
 	}
-	if r.To == "" {
-		return fmt.Errorf("to currency is required")
+	r.From = from
+
+	if len(r.To) == 0 {
+		return NewError(ErrInvalidRequest, "at least one target currency is required", map[string]interface{}{"field": "to"})
 	}
-	if r.Amount.IsZero() || r.Amount.IsNegative() {
-		return fmt.Errorf("amount must be positive")
+	seen := make(map[string]bool, len(r.To))
+	targets := make([]string, 0, len(r.To))
+	for _, code := range r.To {
+		to, err := NormalizeCurrency("to", code)
+		if err != nil {
+			return err
+		}
+		if to == from {
+			return NewError(ErrSameCurrency, "", map[string]interface{}{"field": "to", "currency": to})
+		}
+		if !seen[to] {
+			seen[to] = true
+			targets = append(targets, to)
+		}
 	}
-	if err := r.Amount.Validate(); err != nil {
-		return fmt.Errorf("invalid amount: %w", err)
+	r.To = targets
+
+	return validateAmount(r.Amount)
+}
+
+// Validate normalizes From and To to upper case and checks the request.
+func (r *ConversionRequest) Validate() error {
+	from, to, err := NormalizeCurrencyPair(r.From, r.To)
+	if err != nil {
+		return err
+	}
+	r.From, r.To = from, to
+	if r.FixedSide == "" {
+		r.FixedSide = FixedFrom
+	}
+
+	if err := validateAmount(r.Amount); err != nil {
+		return err
 	}
 	if r.Date.After(time.Now()) {
-		return fmt.Errorf("date cannot be in the future")
+		return NewError(ErrDateOutOfRange, "date cannot be in the future", map[string]interface{}{"field": "date"})
+	}
+	if !r.Date.IsZero() && r.Date.Before(time.Now().AddDate(0, 0, -MaxHistoricalDays)) {
+		return NewError(ErrDateOutOfRange, fmt.Sprintf("date is too old (max %d days)", MaxHistoricalDays), map[string]interface{}{"field": "date", "max_age_days": MaxHistoricalDays})
+	}
+	if r.MaxSlippageBps < 0 || r.MaxSlippageBps > MaxSlippageBps {
+		return NewError(ErrInvalidRequest, fmt.Sprintf("max_slippage_bps must be between 0 and %d", MaxSlippageBps), map[string]interface{}{"field": "max_slippage_bps"})
+	}
+	if r.ExpectedRate == nil {
+		if r.MaxSlippageBps != 0 {
+			return NewError(ErrInvalidRequest, "max_slippage_bps requires expected_rate", map[string]interface{}{"field": "expected_rate"})
+		}
+		return nil
 	}
-	if !r.Date.IsZero() && r.Date.Before(time.Now().AddDate(0, 0, -90)) {
-		return fmt.Errorf("date is too old (max 90 days)")
+	if !r.ExpectedRate.IsPositive() {
+		return NewError(ErrInvalidRequest, "expected_rate must be positive", map[string]interface{}{"field": "expected_rate"})
+	}
+	// CheckSlippage rescales to the expected rate's scale, so it is bounded
+	// like any amount.
+	if err := r.ExpectedRate.Validate(); err != nil {
+		kind := ErrInvalidRequest
+		if errors.Is(err, ErrOverflow) {
+			kind = ErrAmountOverflow
+		}
+		return NewError(kind, fmt.Sprintf("invalid expected_rate: %v", err), map[string]interface{}{"field": "expected_rate"})
 	}
 	return nil
 }
+
+// CheckSlippage rejects rate when it strays from ExpectedRate by more than
+// MaxSlippageBps. The comparison is exact: |rate - expected| * 10000 must
+// not exceed expected * MaxSlippageBps.
+func (r *ConversionRequest) CheckSlippage(rate Money) error {
+	if r.ExpectedRate == nil {
+		return nil
+	}
+	expected := *r.ExpectedRate
+	deviation := rate.Subtract(expected).Abs().MultiplyExact(Money{Amount: big.NewInt(10000)})
+	tolerance := expected.MultiplyExact(Money{Amount: big.NewInt(int64(r.MaxSlippageBps))})
+	if deviation.Cmp(tolerance) <= 0 {
+		return nil
+	}
+	return NewError(ErrSlippageExceeded, "", map[string]interface{}{
+		"expected_rate":    expected.String(),
+		"current_rate":     rate.String(),
+		"max_slippage_bps": r.MaxSlippageBps,
+		"slippage_bps":     deviation.Quo(expected, 2, RoundCeiling).String(),
+	})
+}
+
+// validateAmount checks that a requested amount is positive and in range.
+func validateAmount(amount Money) error {
+	if amount.IsZero() || amount.IsNegative() {
+		return NewError(ErrInvalidAmount, "amount must be positive", map[string]interface{}{"field": "amount"})
+	}
+	if err := amount.Validate(); err != nil {
+		kind := ErrInvalidAmount
+		if errors.Is(err, ErrOverflow) {
+			kind = ErrAmountOverflow
+		}
+		return NewError(kind, fmt.Sprintf("invalid amount: %v", err), map[string]interface{}{"field": "amount"})
+	}
+	return nil
+}
+
+// IsHistorical reports whether the request asks for a day before today
+// (UTC), which has to be priced at that day's rate.
+func (r *ConversionRequest) IsHistorical() bool {
+	return !r.Date.IsZero() && r.Date.Before(time.Now().UTC().Truncate(24*time.Hour))
+}
+
+// NormalizeCurrencyPair upper-cases both codes and checks that they are
+// distinct currencies enabled in the registry.
+func NormalizeCurrencyPair(from, to string) (string, string, error) {
+	from, err := NormalizeCurrency("from", from)
+	if err != nil {
+		return "", "", err
+	}
+	to, err = NormalizeCurrency("to", to)
+	if err != nil {
+		return "", "", err
+	}
+	if from == to {
+		return "", "", NewError(ErrSameCurrency, "", map[string]interface{}{"field": "to", "currency": to})
+	}
+	return from, to, nil
+}
+
+// NormalizeCurrency upper-cases code and checks it is enabled; field names
+// the request field in the returned error.
+func NormalizeCurrency(field, code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", NewError(ErrInvalidRequest, field+" currency is required", map[string]interface{}{"field": field})
+	}
+	if !IsValidCurrency(code) {
+		return "", NewError(ErrUnsupportedCurrency, "unsupported currency: "+code, map[string]interface{}{"field": field, "currency": code})
+	}
+	return code, nil
+}
@@ -1,24 +1,70 @@
 package domain
 
-type Currency struct {
-	Code   string `json:"code"`
-	Name   string `json:"name"`
-	Symbol string `json:"symbol"`
+import (
+	"strings"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/currency"
+)
+
+// Currency is the ISO 4217 entry held by the currency registry.
+type Currency = currency.Currency
+
+// CurrencyInfo is a currency's registry metadata together with the latest
+// cached rate against the base currency, when there is one.
+type CurrencyInfo struct {
+	Currency
+	LatestRate *RateFreshness `json:"latest_rate,omitempty"`
 }
 
-var SupportedCurrencies = []Currency{
-	{"USD", "US Dollar", "$"},
-	{"INR", "Indian Rupee", "₹"},
-	{"EUR", "Euro", "€"},
-	{"JPY", "Japanese Yen", "¥"},
-	{"GBP", "British Pound", "£"},
+type RateFreshness struct {
+	Base       string    `json:"base"`
+	Rate       Money     `json:"rate"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	AgeSeconds int64     `json:"age_seconds"`
 }
 
-func IsValidCurrency(code string) bool {
-	for _, currency := range SupportedCurrencies {
-		if currency.Code == code {
+// CurrencyFilter narrows a currency listing. Empty fields match anything.
+type CurrencyFilter struct {
+	Prefix  string `json:"prefix,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+func (f CurrencyFilter) Matches(c Currency) bool {
+	if f.Prefix != "" && !strings.HasPrefix(c.Code, strings.ToUpper(f.Prefix)) {
+		return false
+	}
+	if f.Country == "" {
+		return true
+	}
+	for _, country := range c.Countries {
+		if strings.EqualFold(country, f.Country) {
 			return true
 		}
 	}
 	return false
 }
+
+// EnabledCurrencies lists the currencies this deployment accepts, sorted
+// by code.
+func EnabledCurrencies() []Currency {
+	return currency.Default().Enabled()
+}
+
+func IsValidCurrency(code string) bool {
+	return currency.Default().IsEnabled(code)
+}
+
+// GetCurrency looks code up in the registry, enabled or not.
+func GetCurrency(code string) (Currency, bool) {
+	return currency.Default().Lookup(code)
+}
+
+// MinorUnits returns the ISO 4217 minor-unit exponent for code, falling
+// back to CentScale, the exponent of most currencies, when it is unknown.
+func MinorUnits(code string) int {
+	if c, ok := GetCurrency(code); ok {
+		return c.MinorUnits
+	}
+	return CentScale
+}
@@ -0,0 +1,77 @@
+package domain
+
+// Stable error codes returned to clients. They are part of the API: never
+// rename one, add a new code instead.
+const (
+	ErrCodeInvalidRequest      = "invalid_request"
+	ErrCodeInvalidAmount       = "invalid_amount"
+	ErrCodeInvalidDate         = "invalid_date"
+	ErrCodeDateOutOfRange      = "date_out_of_range"
+	ErrCodeUnsupportedCurrency = "unsupported_currency"
+	ErrCodeSameCurrency        = "same_currency"
+	ErrCodeCurrencyNotFound    = "currency_not_found"
+	ErrCodeRateNotFound        = "rate_not_found"
+	ErrCodeUpstreamUnavailable = "upstream_unavailable"
+	ErrCodeAmountOverflow      = "amount_overflow"
+	ErrCodeBatchTooLarge       = "batch_too_large"
+	ErrCodeQuoteNotFound       = "quote_not_found"
+	ErrCodeQuoteExpired        = "quote_expired"
+	ErrCodeSlippageExceeded    = "rate_slippage_exceeded"
+	ErrCodeInternal            = "internal_error"
+)
+
+// Error is a failure reported to clients with a stable code. The exported
+// Err* values are kinds: errors.Is(err, ErrRateNotFound) matches any *Error
+// carrying that code, whatever its message or details.
+type Error struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Err     error                  `json:"-"`
+}
+
+var (
+	ErrInvalidRequest      = &Error{Code: ErrCodeInvalidRequest, Message: "invalid request"}
+	ErrInvalidAmount       = &Error{Code: ErrCodeInvalidAmount, Message: "invalid amount"}
+	ErrInvalidDate         = &Error{Code: ErrCodeInvalidDate, Message: "invalid date"}
+	ErrDateOutOfRange      = &Error{Code: ErrCodeDateOutOfRange, Message: "date out of range"}
+	ErrUnsupportedCurrency = &Error{Code: ErrCodeUnsupportedCurrency, Message: "unsupported currency"}
+	ErrSameCurrency        = &Error{Code: ErrCodeSameCurrency, Message: "from and to currencies must differ"}
+	ErrCurrencyNotFound    = &Error{Code: ErrCodeCurrencyNotFound, Message: "currency not found"}
+	ErrRateNotFound        = &Error{Code: ErrCodeRateNotFound, Message: "exchange rate not found"}
+	ErrUpstreamUnavailable = &Error{Code: ErrCodeUpstreamUnavailable, Message: "exchange rate provider unavailable"}
+	ErrAmountOverflow      = &Error{Code: ErrCodeAmountOverflow, Message: "amount out of range"}
+	ErrBatchTooLarge       = &Error{Code: ErrCodeBatchTooLarge, Message: "too many conversions in one batch"}
+	ErrQuoteNotFound       = &Error{Code: ErrCodeQuoteNotFound, Message: "quote not found"}
+	ErrQuoteExpired        = &Error{Code: ErrCodeQuoteExpired, Message: "quote has expired"}
+	ErrSlippageExceeded    = &Error{Code: ErrCodeSlippageExceeded, Message: "rate moved beyond the allowed slippage"}
+	ErrInternal            = &Error{Code: ErrCodeInternal, Message: "internal error"}
+)
+
+// NewError returns an error of the given kind with its own message and
+// details. An empty message keeps the kind's default.
+func NewError(kind *Error, message string, details map[string]interface{}) *Error {
+	if message == "" {
+		message = kind.Message
+	}
+	return &Error{Code: kind.Code, Message: message, Details: details}
+}
+
+// WrapError returns an error of the given kind caused by err.
+func WrapError(kind *Error, err error, details map[string]interface{}) *Error {
+	return &Error{Code: kind.Code, Message: kind.Message, Details: details, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
@@ -14,6 +14,7 @@ type ExchangeRateResponse struct {
 	Rate      Money     `json:"rate"`
 	Amount    Money     `json:"amount"`
 	Timestamp time.Time `json:"timestamp"`
+	Sources   []string  `json:"sources,omitempty"` // providers a consensus rate was computed from
 }
 
 type RateCache struct {
@@ -42,9 +43,10 @@ func (c *RateCache) IsStale(maxAge time.Duration) bool {
 	return time.Since(c.LastFetch) > maxAge
 }
 
+// CrossRate derives from->to through base. Either side may be base itself.
 func (c *RateCache) CrossRate(from, to, base string) Money {
-	fromRate := c.GetPrecisionRate(base, from)
-	toRate := c.GetPrecisionRate(base, to)
+	fromRate := c.baseLeg(base, from)
+	toRate := c.baseLeg(base, to)
 
 	if fromRate.IsZero() || toRate.IsZero() {
 		return Money{}
@@ -53,11 +55,24 @@ func (c *RateCache) CrossRate(from, to, base string) Money {
 	return toRate.Divide(fromRate)
 }
 
+// baseLeg is the base->code rate, which is exactly one for base itself.
+func (c *RateCache) baseLeg(base, code string) Money {
+	if code == base {
+		return NewMoney(1, DefaultScale)
+	}
+	return c.GetPrecisionRate(base, code)
+}
+
+// Has reports whether the cache can price code against base.
+func (c *RateCache) Has(base, code string) bool {
+	return !c.baseLeg(base, code).IsZero()
+}
+
 func (e *ExchangeRate) ConvertToMoney() ExchangeRate {
 	return ExchangeRate{
 		From: e.From,
 		To:   e.To,
-		Rate: NewMoney(float64(e.Rate.Amount), DefaultScale),
+		Rate: e.Rate.ConvertToScale(DefaultScale),
 		Date: e.Date,
 	}
 }
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HistoryInterval is the spacing between points of a rate time series.
+type HistoryInterval string
+
+const (
+	IntervalDay   HistoryInterval = "day"
+	IntervalWeek  HistoryInterval = "week"
+	IntervalMonth HistoryInterval = "month"
+)
+
+// ParseHistoryInterval parses an interval name; the empty string means
+// IntervalDay.
+func ParseHistoryInterval(s string) (HistoryInterval, error) {
+	switch interval := HistoryInterval(strings.ToLower(strings.TrimSpace(s))); interval {
+	case "":
+		return IntervalDay, nil
+	case IntervalDay, IntervalWeek, IntervalMonth:
+		return interval, nil
+	default:
+		return "", fmt.Errorf("unknown interval %q (want day, week or month)", s)
+	}
+}
+
+// RateHistoryRequest asks for the rates of one pair between Start and End,
+// both inclusive, sampled every Interval.
+type RateHistoryRequest struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Interval HistoryInterval `json:"interval"`
+}
+
+// RatePoint is one date of a series. A point the provider could not price
+// is kept as a gap: Rate is nil and Error says why.
+type RatePoint struct {
+	Date  string `json:"date"`
+	Rate  *Money `json:"rate,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// RateHistoryResponse lists every sampled date in ascending order. Success
+// is only set when the series has no gaps.
+type RateHistoryResponse struct {
+	Success  bool            `json:"success"`
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Start    string          `json:"start"`
+	End      string          `json:"end"`
+	Interval HistoryInterval `json:"interval"`
+	Gaps     int             `json:"gaps"`
+	Points   []RatePoint     `json:"points"`
+}
+
+// Validate normalizes the pair, defaults End to today and Interval to
+// IntervalDay, and checks that the range lies within the historical window.
+func (r *RateHistoryRequest) Validate() error {
+	from, to, err := NormalizeCurrencyPair(r.From, r.To)
+	if err != nil {
+		return err
+	}
+	r.From, r.To = from, to
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if r.End.IsZero() {
+		r.End = today
+	}
+	if r.Interval == "" {
+		r.Interval = IntervalDay
+	}
+	if r.Start.IsZero() {
+		return NewError(ErrInvalidDate, "start date is required", map[string]interface{}{"field": "start"})
+	}
+	if r.Start.After(r.End) {
+		return NewError(ErrDateOutOfRange, "start must not be after end", map[string]interface{}{"field": "start"})
+	}
+	if r.End.After(today) {
+		return NewError(ErrDateOutOfRange, "end cannot be in the future", map[string]interface{}{"field": "end"})
+	}
+	if r.Start.Before(today.AddDate(0, 0, -MaxHistoricalDays)) {
+		return NewError(ErrDateOutOfRange, fmt.Sprintf("start is too old (max %d days)", MaxHistoricalDays), map[string]interface{}{"field": "start", "max_age_days": MaxHistoricalDays})
+	}
+	return nil
+}
+
+// Dates returns the sampled dates from Start to End: every day, every
+// seventh day, or the same day of each month clamped to the month's end.
+func (r *RateHistoryRequest) Dates() []time.Time {
+	var dates []time.Time
+	for i := 0; ; i++ {
+		var date time.Time
+		switch r.Interval {
+		case IntervalWeek:
+			date = r.Start.AddDate(0, 0, 7*i)
+		case IntervalMonth:
+			date = addMonths(r.Start, i)
+		default:
+			date = r.Start.AddDate(0, 0, i)
+		}
+		if date.After(r.End) {
+			return dates
+		}
+		dates = append(dates, date)
+	}
+}
+
+// addMonths moves t by n months without overflowing into the next month,
+// so Jan 31 plus one month is the last day of February.
+func addMonths(t time.Time, n int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
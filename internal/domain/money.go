@@ -2,62 +2,156 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
+// Money is a fixed-point decimal backed by an arbitrary-precision integer:
+// the value is Amount * 10^-Scale. Money values are immutable; Amount is
+// never modified in place once a Money has been constructed.
+//
+// MaxDigits is only enforced where values enter: ParseMoney, UnmarshalJSON
+// and Validate. Arithmetic results are not bounded, so code that reports a
+// computed amount calls Validate on it first. The arithmetic methods have
+// no error to return, so they panic when asked for RoundUnnecessary on a
+// result that needs rounding, as big.Int does when dividing by zero.
 type Money struct {
-	Amount int64 `json:"amount"`
-	Scale  int   `json:"scale"`
+	Amount *big.Int `json:"amount"`
+	Scale  int      `json:"scale"`
 }
 
 const (
 	DefaultScale = 6
 	CentScale    = 2
+
+	// MaxScale is the largest number of fractional digits a Money may carry.
+	MaxScale = 18
+	// MaxDigits bounds the number of digits held in Amount. Parsed and
+	// validated values beyond it are rejected with an *OverflowError.
+	MaxDigits = 38
 )
 
+// ErrOverflow is matched by every *OverflowError via errors.Is.
+var ErrOverflow = errors.New("money overflow")
+
+// OverflowError reports a value that does not fit the requested
+// representation (int64, float64, or the MaxDigits bound).
+type OverflowError struct {
+	Op    string
+	Value string
+	Limit string
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("money overflow in %s: %s exceeds %s", e.Op, e.Value, e.Limit)
+}
+
+func (e *OverflowError) Unwrap() error { return ErrOverflow }
+
+// ErrInexact is the panic value, wrapped, of arithmetic that needs rounding
+// under RoundUnnecessary.
+var ErrInexact = errors.New("money result needs rounding")
+
+// mustBeExact passes on a rounded amount, panicking when rounding under
+// RoundUnnecessary dropped digits.
+func mustBeExact(op string, amount *big.Int, exact bool) *big.Int {
+	if !exact {
+		panic(fmt.Errorf("money %s: %w", op, ErrInexact))
+	}
+	return amount
+}
+
+var maxAmount = new(big.Int).Exp(big.NewInt(10), big.NewInt(MaxDigits), nil)
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// NewMoney builds a Money from a float, rounding half away from zero at the
+// given scale. Non-finite inputs yield a zero Money; use NewMoneyFromFloat
+// when the caller needs to know about them.
 func NewMoney(value float64, scale int) Money {
+	m, err := NewMoneyFromFloat(value, scale)
+	if err != nil {
+		return Money{Amount: new(big.Int), Scale: m.Scale}
+	}
+	return m
+}
+
+// NewMoneyFromFloat is NewMoney with an error for NaN and infinite inputs.
+func NewMoneyFromFloat(value float64, scale int) (Money, error) {
 	if scale < 0 {
 		scale = DefaultScale
 	}
-	multiplier := int64(math.Pow10(scale))
-	return Money{
-		Amount: int64(math.Round(value * float64(multiplier))),
-		Scale:  scale,
+	scaled := math.Round(value * math.Pow10(scale))
+	if math.IsNaN(scaled) || math.IsInf(scaled, 0) {
+		return Money{Scale: scale}, &OverflowError{Op: "NewMoney", Value: strconv.FormatFloat(value, 'g', -1, 64), Limit: "float64 range"}
 	}
+	amount, _ := new(big.Float).SetFloat64(scaled).Int(nil)
+	return Money{Amount: amount, Scale: scale}, nil
 }
 
+// NewMoneyFromString parses value exactly at the given scale, rejecting
+// input with more fractional digits than scale. See ParseMoney to round
+// such input instead.
 func NewMoneyFromString(value string, scale int) (Money, error) {
-	val, err := strconv.ParseFloat(value, 64)
-	if err != nil {
-		return Money{}, fmt.Errorf("invalid money format: %s", value)
+	return ParseMoney(value, scale, RoundUnnecessary)
+}
+
+// amount returns the backing integer, treating the zero Money as 0.
+func (m Money) amount() *big.Int {
+	if m.Amount == nil {
+		return new(big.Int)
 	}
-	return NewMoney(val, scale), nil
+	return m.Amount
 }
 
 func (m Money) ToFloat() float64 {
-	if m.Scale == 0 {
-		return float64(m.Amount)
+	f := new(big.Float).SetInt(m.amount())
+	if m.Scale != 0 {
+		f.Quo(f, new(big.Float).SetInt(pow10(m.Scale)))
 	}
-	divisor := math.Pow10(m.Scale)
-	return float64(m.Amount) / divisor
+	value, _ := f.Float64()
+	return value
+}
+
+// Int64 returns the raw scaled amount, or an *OverflowError when it does
+// not fit in an int64.
+func (m Money) Int64() (int64, error) {
+	a := m.amount()
+	if !a.IsInt64() {
+		return 0, &OverflowError{Op: "Int64", Value: a.String(), Limit: "int64"}
+	}
+	return a.Int64(), nil
 }
 
 func (m Money) String() string {
-	if m.Scale == 0 {
-		return strconv.FormatInt(m.Amount, 10)
+	if m.Scale <= 0 {
+		return new(big.Int).Mul(m.amount(), pow10(-m.Scale)).String()
 	}
 
-	value := m.ToFloat()
-	return fmt.Sprintf("%."+strconv.Itoa(m.Scale)+"f", value)
+	digits := new(big.Int).Abs(m.amount()).String()
+	if len(digits) <= m.Scale {
+		digits = strings.Repeat("0", m.Scale-len(digits)+1) + digits
+	}
+
+	point := len(digits) - m.Scale
+	sign := ""
+	if m.IsNegative() {
+		sign = "-"
+	}
+	return sign + digits[:point] + "." + digits[point:]
 }
 
 // Add performs precise addition
 func (m Money) Add(other Money) Money {
 	normalized := m.normalizeScale(other)
 	return Money{
-		Amount: normalized.m1.Amount + normalized.m2.Amount,
+		Amount: new(big.Int).Add(normalized.m1.amount(), normalized.m2.amount()),
 		Scale:  normalized.scale,
 	}
 }
@@ -66,20 +160,22 @@ func (m Money) Add(other Money) Money {
 func (m Money) Subtract(other Money) Money {
 	normalized := m.normalizeScale(other)
 	return Money{
-		Amount: normalized.m1.Amount - normalized.m2.Amount,
+		Amount: new(big.Int).Sub(normalized.m1.amount(), normalized.m2.amount()),
 		Scale:  normalized.scale,
 	}
 }
 
-// Multiply performs precise multiplication with another Money (for rates)
-func (m Money) Multiply(rate Money) Money {
-	result := m.Amount * rate.Amount
+// Multiply performs precise multiplication with another Money (for rates).
+// Digits beyond DefaultScale are dropped according to the optional mode,
+// which defaults to RoundDown.
+func (m Money) Multiply(rate Money, mode ...RoundingMode) Money {
+	result := new(big.Int).Mul(m.amount(), rate.amount())
 	newScale := m.Scale + rate.Scale
 
 	// Normalize to target precision (DefaultScale)
 	if newScale > DefaultScale {
-		divisor := int64(math.Pow10(newScale - DefaultScale))
-		result = result / divisor
+		rounded, exact := roundQuo(result, pow10(newScale-DefaultScale), roundingMode(mode))
+		result = mustBeExact("Multiply", rounded, exact)
 		newScale = DefaultScale
 	}
 
@@ -88,50 +184,110 @@ func (m Money) Multiply(rate Money) Money {
 
 // MultiplyByFloat multiplies by a float (use sparingly)
 func (m Money) MultiplyByFloat(multiplier float64) Money {
+	if math.IsNaN(multiplier) || math.IsInf(multiplier, 0) {
+		return Money{Amount: new(big.Int), Scale: m.Scale}
+	}
+	product := new(big.Float).SetInt(m.amount())
+	product.Mul(product, big.NewFloat(multiplier))
+	result, _ := product.Int(nil)
+	return Money{Amount: result, Scale: m.Scale}
+}
+
+// MultiplyExact returns the full product of m and other at scale
+// m.Scale+other.Scale, without the rounding Multiply applies.
+func (m Money) MultiplyExact(other Money) Money {
 	return Money{
-		Amount: int64(float64(m.Amount) * multiplier),
-		Scale:  m.Scale,
+		Amount: new(big.Int).Mul(m.amount(), other.amount()),
+		Scale:  m.Scale + other.Scale,
+	}
+}
+
+// Divide performs precise division, rounding the DefaultScale quotient
+// according to the optional mode (RoundDown when omitted).
+func (m Money) Divide(divisor Money, mode ...RoundingMode) Money {
+	if divisor.IsZero() {
+		return Money{Amount: new(big.Int), Scale: m.Scale}
 	}
+	return m.Quo(divisor, DefaultScale, mode...)
 }
 
-// Divide performs precise division
-func (m Money) Divide(divisor Money) Money {
-	if divisor.Amount == 0 {
-		return Money{Amount: 0, Scale: m.Scale}
+// Quo divides m by divisor, rounding the quotient to scale according to the
+// optional mode (RoundDown when omitted). Dividing by zero yields zero.
+func (m Money) Quo(divisor Money, scale int, mode ...RoundingMode) Money {
+	if divisor.IsZero() {
+		return Money{Amount: new(big.Int), Scale: scale}
 	}
 
 	normalized := m.normalizeScale(divisor)
-	// Add extra precision to avoid truncation
-	numerator := normalized.m1.Amount * int64(math.Pow10(DefaultScale))
-	result := numerator / normalized.m2.Amount
+	numerator := new(big.Int).Mul(normalized.m1.amount(), pow10(scale))
+	result, exact := roundQuo(numerator, normalized.m2.amount(), roundingMode(mode))
 
-	return Money{Amount: result, Scale: DefaultScale}
+	return Money{Amount: mustBeExact("Quo", result, exact), Scale: scale}
+}
+
+// Sqrt returns the square root of m at the given scale, rounded according
+// to the optional mode (RoundDown when omitted). The root of a negative
+// value is zero.
+func (m Money) Sqrt(scale int, mode ...RoundingMode) Money {
+	if m.amount().Sign() <= 0 {
+		return Money{Amount: new(big.Int), Scale: scale}
+	}
+
+	// Take the root with at least one digit more than requested. An inexact
+	// root then gets a trailing 1, which sits on the same side of every
+	// rounding boundary as the irrational true value.
+	work := scale + 1
+	if half := (m.Scale + 1) / 2; half > work {
+		work = half
+	}
+	n := new(big.Int).Mul(m.amount(), pow10(2*work-m.Scale))
+	root := new(big.Int).Sqrt(n)
+	if new(big.Int).Mul(root, root).Cmp(n) != 0 {
+		root.Mul(root, big.NewInt(10))
+		root.Add(root, big.NewInt(1))
+		work++
+	}
+
+	amount, exact := rescale(root, work, scale, roundingMode(mode))
+	return Money{Amount: mustBeExact("Sqrt", amount, exact), Scale: scale}
 }
 
 func (m Money) IsZero() bool {
-	return m.Amount == 0
+	return m.amount().Sign() == 0
 }
 
 func (m Money) IsPositive() bool {
-	return m.Amount > 0
+	return m.amount().Sign() > 0
 }
 
 func (m Money) IsNegative() bool {
-	return m.Amount < 0
+	return m.amount().Sign() < 0
+}
+
+// Cmp compares m and other numerically, regardless of scale, returning
+// -1, 0 or +1.
+func (m Money) Cmp(other Money) int {
+	normalized := m.normalizeScale(other)
+	return normalized.m1.amount().Cmp(normalized.m2.amount())
 }
 
-func (m Money) ConvertToScale(targetScale int) Money {
+func (m Money) Abs() Money {
+	return Money{Amount: new(big.Int).Abs(m.amount()), Scale: m.Scale}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: new(big.Int).Neg(m.amount()), Scale: m.Scale}
+}
+
+// ConvertToScale rescales m, rounding dropped digits according to the
+// optional mode (RoundDown when omitted).
+func (m Money) ConvertToScale(targetScale int, mode ...RoundingMode) Money {
 	if m.Scale == targetScale {
 		return m
 	}
 
-	if targetScale > m.Scale {
-		multiplier := int64(math.Pow10(targetScale - m.Scale))
-		return Money{Amount: m.Amount * multiplier, Scale: targetScale}
-	}
-
-	divisor := int64(math.Pow10(m.Scale - targetScale))
-	return Money{Amount: m.Amount / divisor, Scale: targetScale}
+	amount, exact := rescale(m.amount(), m.Scale, targetScale, roundingMode(mode))
+	return Money{Amount: mustBeExact("ConvertToScale", amount, exact), Scale: targetScale}
 }
 
 type normalizedPair struct {
@@ -154,7 +310,7 @@ func (m Money) normalizeScale(other Money) normalizedPair {
 
 func (m Money) MarshalJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
-		"amount": m.Amount,
+		"amount": m.amount(),
 		"scale":  m.Scale,
 		"value":  m.String(),
 	})
@@ -162,9 +318,9 @@ func (m Money) MarshalJSON() ([]byte, error) {
 
 func (m *Money) UnmarshalJSON(data []byte) error {
 	var temp struct {
-		Amount *int64 `json:"amount"`
-		Scale  *int   `json:"scale"`
-		Value  string `json:"value"`
+		Amount *big.Int `json:"amount"`
+		Scale  *int     `json:"scale"`
+		Value  string   `json:"value"`
 	}
 
 	if err := json.Unmarshal(data, &temp); err != nil {
@@ -172,13 +328,24 @@ func (m *Money) UnmarshalJSON(data []byte) error {
 	}
 
 	if temp.Amount != nil && temp.Scale != nil {
-		m.Amount = *temp.Amount
-		m.Scale = *temp.Scale
+		parsed := Money{Amount: temp.Amount, Scale: *temp.Scale}
+		if err := parsed.checkDigits("UnmarshalJSON"); err != nil {
+			return err
+		}
+		*m = parsed
 		return nil
 	}
 
 	if temp.Value != "" {
-		parsed, err := NewMoneyFromString(temp.Value, DefaultScale)
+		// Without an explicit scale, keep every digit the client sent.
+		scale := DefaultScale
+		if temp.Scale != nil {
+			scale = *temp.Scale
+		} else if natural := naturalScale(temp.Value); natural > scale {
+			scale = min(natural, MaxScale)
+		}
+
+		parsed, err := NewMoneyFromString(temp.Value, scale)
 		if err != nil {
 			return err
 		}
@@ -193,8 +360,16 @@ func (m Money) Validate() error {
 	if m.Scale < 0 {
 		return fmt.Errorf("scale cannot be negative")
 	}
-	if m.Scale > 18 {
-		return fmt.Errorf("scale too large (max 18)")
+	if m.Scale > MaxScale {
+		return fmt.Errorf("scale too large (max %d)", MaxScale)
+	}
+	return m.checkDigits("Validate")
+}
+
+// checkDigits reports an *OverflowError when the amount exceeds MaxDigits.
+func (m Money) checkDigits(op string) error {
+	if m.amount().CmpAbs(maxAmount) >= 0 {
+		return &OverflowError{Op: op, Value: m.amount().String(), Limit: fmt.Sprintf("%d digits", MaxDigits)}
 	}
 	return nil
 }
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxExponent bounds the exponent accepted by the parser so that inputs
+// like "1e999999" are rejected before any big.Int work is done.
+const maxExponent = MaxDigits + MaxScale
+
+// ParseMoney parses a decimal string without going through float64. It
+// accepts an optional sign, leading and trailing zeros and exponent
+// notation ("1.5e3"). Digits beyond scale are handled according to mode;
+// with RoundUnnecessary they are an error.
+func ParseMoney(value string, scale int, mode RoundingMode) (Money, error) {
+	if scale < 0 {
+		scale = DefaultScale
+	}
+	if scale > MaxScale {
+		return Money{}, fmt.Errorf("scale too large (max %d)", MaxScale)
+	}
+
+	coefficient, fracDigits, err := parseDecimal(value)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount, exact := rescale(coefficient, fracDigits, scale, mode)
+	if !exact {
+		return Money{}, fmt.Errorf("invalid money format: %s has more than %d fractional digits", value, scale)
+	}
+
+	m := Money{Amount: amount, Scale: scale}
+	if err := m.checkDigits("ParseMoney"); err != nil {
+		return Money{}, err
+	}
+	return m, nil
+}
+
+// parseDecimal splits a decimal string into an integer coefficient and the
+// number of fractional digits it carries, so that the value is
+// coefficient * 10^-fracDigits. fracDigits may be negative.
+func parseDecimal(value string) (*big.Int, int, error) {
+	invalid := fmt.Errorf("invalid money format: %s", value)
+
+	s := strings.TrimSpace(value)
+	negative := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	exponent := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, 0, invalid
+		}
+		if exp > maxExponent || exp < -maxExponent {
+			return nil, 0, &OverflowError{Op: "ParseMoney", Value: value, Limit: fmt.Sprintf("exponent %d", maxExponent)}
+		}
+		exponent = exp
+		s = s[:i]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return nil, 0, invalid
+	}
+
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return nil, 0, invalid
+		}
+	}
+	if len(digits) > MaxDigits+MaxScale {
+		return nil, 0, &OverflowError{Op: "ParseMoney", Value: value, Limit: fmt.Sprintf("%d digits", MaxDigits+MaxScale)}
+	}
+
+	coefficient := new(big.Int)
+	if digits != "" {
+		if _, ok := coefficient.SetString(digits, 10); !ok {
+			return nil, 0, invalid
+		}
+	}
+	if negative {
+		coefficient.Neg(coefficient)
+	}
+	return coefficient, len(fracPart) - exponent, nil
+}
+
+// rescale moves coefficient * 10^-fromScale to toScale. It reports false
+// when digits had to be dropped under RoundUnnecessary.
+func rescale(coefficient *big.Int, fromScale, toScale int, mode RoundingMode) (*big.Int, bool) {
+	if toScale >= fromScale {
+		return new(big.Int).Mul(coefficient, pow10(toScale-fromScale)), true
+	}
+	return roundQuo(coefficient, pow10(fromScale-toScale), mode)
+}
+
+// naturalScale returns the number of fractional digits value is written
+// with, or zero for integers and inputs that do not parse.
+func naturalScale(value string) int {
+	_, fracDigits, err := parseDecimal(value)
+	if err != nil || fracDigits < 0 {
+		return 0
+	}
+	return fracDigits
+}
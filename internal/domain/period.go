@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPeriodHistoryDays is how far back an accounting period may start. It
+// is longer than MaxHistoricalDays because a calendar quarter alone runs up
+// to 92 days, so a quarter that has just ended already starts beyond that
+// window; a year back covers the last four quarters.
+const MaxPeriodHistoryDays = 366
+
+// MaxClosingLookbackDays bounds how far before a period end a closing rate
+// is searched for, enough to step over a long weekend plus holidays.
+const MaxClosingLookbackDays = 7
+
+// WeekendPolicy says whether Saturdays and Sundays take part in a period
+// rate. Markets do not fix rates at weekends, so they are excluded unless
+// asked for; any other day without a rate is reported as missing.
+type WeekendPolicy string
+
+const (
+	WeekendsExclude WeekendPolicy = "exclude"
+	WeekendsInclude WeekendPolicy = "include"
+)
+
+// ParseWeekendPolicy parses a policy name; the empty string means
+// WeekendsExclude.
+func ParseWeekendPolicy(s string) (WeekendPolicy, error) {
+	switch policy := WeekendPolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case "":
+		return WeekendsExclude, nil
+	case WeekendsExclude, WeekendsInclude:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown weekends policy %q (want exclude or include)", s)
+	}
+}
+
+// Skips reports whether the policy leaves date out.
+func (p WeekendPolicy) Skips(date time.Time) bool {
+	weekday := date.Weekday()
+	return p != WeekendsInclude && (weekday == time.Saturday || weekday == time.Sunday)
+}
+
+// ParsePeriod parses a calendar month ("2025-03") or quarter ("2025-Q1")
+// into its first and last day.
+func ParsePeriod(s string) (time.Time, time.Time, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if year, quarter, ok := strings.Cut(s, "-Q"); ok {
+		y, yerr := strconv.Atoi(year)
+		q, qerr := strconv.Atoi(quarter)
+		if yerr != nil || qerr != nil || len(year) != 4 || q < 1 || q > 4 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid quarter %q, expected YYYY-Qn", s)
+		}
+		start := time.Date(y, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, -1), nil
+	}
+
+	start, err := time.Parse("2006-01", s)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q, expected YYYY-MM or YYYY-Qn", s)
+	}
+	return start, start.AddDate(0, 1, -1), nil
+}
+
+// PeriodRateRequest asks for an accounting rate of one pair. Averages use
+// every day from Start to End; closing rates only use End. The result is
+// rounded to Scale with Rounding.
+type PeriodRateRequest struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Scale    int           `json:"scale"`
+	Rounding RoundingMode  `json:"rounding"`
+	Weekends WeekendPolicy `json:"weekends"`
+}
+
+// PeriodAverageResponse is the arithmetic mean of the rates on RateDates.
+// SkippedDates were left out by the weekend policy; MissingDates had no
+// rate, typically market holidays.
+type PeriodAverageResponse struct {
+	Success      bool          `json:"success"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Start        string        `json:"start"`
+	End          string        `json:"end"`
+	Rate         Money         `json:"rate"`
+	Rounding     RoundingMode  `json:"rounding"`
+	Weekends     WeekendPolicy `json:"weekends"`
+	RateDates    []string      `json:"rate_dates"`
+	SkippedDates []string      `json:"skipped_dates"`
+	MissingDates []string      `json:"missing_dates"`
+}
+
+// ClosingRateResponse is the last rate available on or before PeriodEnd,
+// observed on RateDate. SkippedDates lists the later days stepped over.
+type ClosingRateResponse struct {
+	Success      bool          `json:"success"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	PeriodEnd    string        `json:"period_end"`
+	RateDate     string        `json:"rate_date"`
+	Rate         Money         `json:"rate"`
+	Rounding     RoundingMode  `json:"rounding"`
+	Weekends     WeekendPolicy `json:"weekends"`
+	SkippedDates []string      `json:"skipped_dates"`
+}
+
+// Validate normalizes the pair and checks the period against the
+// accounting window, MaxPeriodHistoryDays. A zero Start is only valid for
+// closing rates, which set it to End.
+func (r *PeriodRateRequest) Validate() error {
+	from, to, err := NormalizeCurrencyPair(r.From, r.To)
+	if err != nil {
+		return err
+	}
+	r.From, r.To = from, to
+
+	if r.Scale < 0 || r.Scale > MaxScale {
+		return NewError(ErrInvalidRequest, fmt.Sprintf("scale must be between 0 and %d", MaxScale), map[string]interface{}{"field": "scale"})
+	}
+	if r.Weekends == "" {
+		r.Weekends = WeekendsExclude
+	}
+	if r.End.IsZero() {
+		return NewError(ErrInvalidDate, "period end is required", map[string]interface{}{"field": "end"})
+	}
+	if r.Start.IsZero() {
+		r.Start = r.End
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if r.Start.After(r.End) {
+		return NewError(ErrDateOutOfRange, "start must not be after end", map[string]interface{}{"field": "start"})
+	}
+	if r.End.After(today) {
+		return NewError(ErrDateOutOfRange, "period has not ended yet", map[string]interface{}{"field": "end", "end": r.End.Format("2006-01-02")})
+	}
+	if r.Start.Before(today.AddDate(0, 0, -MaxPeriodHistoryDays)) {
+		return NewError(ErrDateOutOfRange, fmt.Sprintf("period starts too far back (max %d days)", MaxPeriodHistoryDays), map[string]interface{}{"field": "start", "max_age_days": MaxPeriodHistoryDays})
+	}
+	return nil
+}
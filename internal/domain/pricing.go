@@ -0,0 +1,26 @@
+package domain
+
+// PricedRate is a mid rate with the customer spread applied: customers
+// converting From into To get Bid, and Ask is the rate for the other
+// direction. SpreadBps is the markup on each side of Mid.
+type PricedRate struct {
+	Mid       Money  `json:"mid"`
+	Bid       Money  `json:"bid"`
+	Ask       Money  `json:"ask"`
+	SpreadBps int    `json:"spread_bps"`
+	Segment   string `json:"segment,omitempty"`
+	// Sources names the providers whose rates made up Mid, when the
+	// provider reports them.
+	Sources []string `json:"sources,omitempty"`
+	// RateDate is the day the provider published Mid for, when it says.
+	RateDate string `json:"rate_date,omitempty"`
+}
+
+// FeeItem is one fee charged on a conversion, in Currency: the source
+// currency for Side "source", the target currency for "target".
+type FeeItem struct {
+	Name     string `json:"name"`
+	Side     string `json:"side"`
+	Currency string `json:"currency"`
+	Amount   Money  `json:"amount"`
+}
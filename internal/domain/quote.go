@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Quote locks a live customer rate for one conversion until ExpiresAt.
+// Result and UnroundedResult are what executing it will return.
+type Quote struct {
+	ID              string       `json:"id"`
+	From            string       `json:"from"`
+	To              string       `json:"to"`
+	Amount          Money        `json:"amount"`
+	Rate            Money        `json:"rate"`
+	MidRate         Money        `json:"mid_rate"`
+	SpreadBps       int          `json:"spread_bps"`
+	Segment         string       `json:"segment,omitempty"`
+	Result          Money        `json:"result"`
+	UnroundedResult Money        `json:"unrounded_result"`
+	Rounding        RoundingMode `json:"rounding"`
+	FixedSide       FixedSide    `json:"fixed_side"`
+	CreatedAt       time.Time    `json:"created_at"`
+	ExpiresAt       time.Time    `json:"expires_at"`
+}
+
+// Expired reports whether the quote can no longer be executed at t.
+func (q Quote) Expired(t time.Time) bool {
+	return !t.Before(q.ExpiresAt)
+}
+
+type QuoteResponse struct {
+	Success bool  `json:"success"`
+	Quote   Quote `json:"quote"`
+}
+
+// QuoteExecutionResponse is the conversion performed at the quote's locked
+// rate. A quote can be executed once.
+type QuoteExecutionResponse struct {
+	Success    bool               `json:"success"`
+	QuoteID    string             `json:"quote_id"`
+	ExecutedAt time.Time          `json:"executed_at"`
+	Conversion ConversionResponse `json:"conversion"`
+}
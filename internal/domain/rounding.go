@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RoundingMode selects what happens to digits beyond a target scale.
+type RoundingMode int
+
+const (
+	// RoundDown truncates toward zero. It is the zero value, so arithmetic
+	// that is not given a mode keeps its historical truncating behaviour.
+	RoundDown RoundingMode = iota
+	// RoundHalfUp rounds to nearest, ties away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to nearest, ties toward zero.
+	RoundHalfDown
+	// RoundHalfEven rounds to nearest, ties to the even neighbour (banker's).
+	RoundHalfEven
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundUnnecessary rejects any value that would need rounding: parsing
+	// returns an error, and arithmetic, which has no error to return,
+	// panics with ErrInexact.
+	RoundUnnecessary
+)
+
+var roundingModeNames = map[RoundingMode]string{
+	RoundDown:        "down",
+	RoundHalfUp:      "half_up",
+	RoundHalfDown:    "half_down",
+	RoundHalfEven:    "half_even",
+	RoundCeiling:     "ceiling",
+	RoundFloor:       "floor",
+	RoundUnnecessary: "unnecessary",
+}
+
+// ParseRoundingMode maps a mode name such as "half_even" to its
+// RoundingMode. Matching is case-insensitive and accepts hyphens.
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	if normalized == "" || normalized == "truncate" {
+		return RoundDown, nil
+	}
+	for mode, modeName := range roundingModeNames {
+		if modeName == normalized {
+			return mode, nil
+		}
+	}
+	return RoundDown, fmt.Errorf("unknown rounding mode: %s", name)
+}
+
+func (r RoundingMode) String() string {
+	if name, ok := roundingModeNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(r))
+}
+
+func (r RoundingMode) MarshalText() ([]byte, error) {
+	if _, ok := roundingModeNames[r]; !ok {
+		return nil, fmt.Errorf("unknown rounding mode: %d", int(r))
+	}
+	return []byte(r.String()), nil
+}
+
+func (r *RoundingMode) UnmarshalText(text []byte) error {
+	mode, err := ParseRoundingMode(string(text))
+	if err != nil {
+		return err
+	}
+	*r = mode
+	return nil
+}
+
+// roundingMode picks the optional mode passed to a Money method.
+func roundingMode(modes []RoundingMode) RoundingMode {
+	if len(modes) > 0 {
+		return modes[0]
+	}
+	return RoundDown
+}
+
+// roundQuo divides n by the non-zero d, rounding the quotient according to
+// mode. It reports false when the division is inexact and mode is
+// RoundUnnecessary; the truncated quotient is returned in that case.
+func roundQuo(n, d *big.Int, mode RoundingMode) (*big.Int, bool) {
+	if d.Sign() < 0 {
+		n = new(big.Int).Neg(n)
+		d = new(big.Int).Neg(d)
+	}
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q, true
+	}
+
+	// r carries the sign of n, and so does the step away from zero.
+	away := big.NewInt(int64(r.Sign()))
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	tie := half.Cmp(d)
+
+	switch mode {
+	case RoundUnnecessary:
+		return q, false
+	case RoundHalfUp:
+		if tie >= 0 {
+			q.Add(q, away)
+		}
+	case RoundHalfDown:
+		if tie > 0 {
+			q.Add(q, away)
+		}
+	case RoundHalfEven:
+		if tie > 0 || (tie == 0 && q.Bit(0) == 1) {
+			q.Add(q, away)
+		}
+	case RoundCeiling:
+		if r.Sign() > 0 {
+			q.Add(q, away)
+		}
+	case RoundFloor:
+		if r.Sign() < 0 {
+			q.Add(q, away)
+		}
+	}
+	return q, true
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "math/big"
+
+// RateStatsResponse summarises a pair's rate over a period. Figures are
+// computed from the priced points of the series only; Gaps counts the
+// dates left out. StdDev is the population standard deviation, and every
+// derived figure is rounded half-even to DefaultScale.
+type RateStatsResponse struct {
+	Success       bool            `json:"success"`
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Start         string          `json:"start"`
+	End           string          `json:"end"`
+	Interval      HistoryInterval `json:"interval"`
+	Points        int             `json:"points"`
+	Gaps          int             `json:"gaps"`
+	StartRate     RatePoint       `json:"start_rate"`
+	EndRate       RatePoint       `json:"end_rate"`
+	Change        Money           `json:"change"`
+	ChangePercent Money           `json:"change_percent"`
+	Min           Money           `json:"min"`
+	Max           Money           `json:"max"`
+	Mean          Money           `json:"mean"`
+	StdDev        Money           `json:"std_dev"`
+}
+
+// SummarizeRates fills the statistics of resp from the priced points of
+// history. It reports false when no point carries a rate.
+func SummarizeRates(history *RateHistoryResponse, resp *RateStatsResponse) bool {
+	var rates []Money
+	for _, point := range history.Points {
+		if point.Rate == nil {
+			continue
+		}
+		if len(rates) == 0 {
+			resp.StartRate = point
+		}
+		resp.EndRate = point
+		rates = append(rates, *point.Rate)
+	}
+	resp.Points = len(rates)
+	resp.Gaps = history.Gaps
+	if len(rates) == 0 {
+		return false
+	}
+
+	count := Money{Amount: big.NewInt(int64(len(rates)))}
+	sum, sumSquares := Money{}, Money{}
+	resp.Min, resp.Max = rates[0], rates[0]
+	for _, rate := range rates {
+		sum = sum.Add(rate)
+		sumSquares = sumSquares.Add(rate.MultiplyExact(rate))
+		if rate.Cmp(resp.Min) < 0 {
+			resp.Min = rate
+		}
+		if rate.Cmp(resp.Max) > 0 {
+			resp.Max = rate
+		}
+	}
+
+	first, last := *resp.StartRate.Rate, *resp.EndRate.Rate
+	resp.Change = last.Subtract(first)
+	hundred := Money{Amount: big.NewInt(100)}
+	resp.ChangePercent = resp.Change.MultiplyExact(hundred).Quo(first, DefaultScale, RoundHalfEven)
+	resp.Mean = sum.Quo(count, DefaultScale, RoundHalfEven)
+
+	// Variance as (n*sum(x^2) - sum(x)^2) / n^2 stays exact until the final
+	// division, unlike summing squared deviations from a rounded mean.
+	spread := sumSquares.MultiplyExact(count).Subtract(sum.MultiplyExact(sum))
+	variance := spread.Quo(count.MultiplyExact(count), MaxScale, RoundHalfEven)
+	resp.StdDev = variance.Sqrt(DefaultScale, RoundHalfEven)
+	return true
+}
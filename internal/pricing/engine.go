@@ -0,0 +1,147 @@
+// Package pricing turns mid rates into customer rates by applying spreads
+// configured per currency pair, amount tier and customer segment.
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// maxSpreadBps keeps a bid rate positive.
+const maxSpreadBps = 9999
+
+var bpsDivisor = domain.Money{Amount: big.NewInt(10000)}
+
+// Tier applies SpreadBps to conversions of at least MinAmount, counted in
+// the pair's From currency.
+type Tier struct {
+	MinAmount domain.Money
+	SpreadBps int
+}
+
+// PairRule sets the spread for one direction of a pair. The largest tier
+// whose MinAmount the amount reaches wins over SpreadBps.
+type PairRule struct {
+	From      string
+	To        string
+	SpreadBps int
+	Tiers     []Tier
+}
+
+// Engine prices mid rates. The zero value charges no spread.
+type Engine struct {
+	defaultBps int
+	segments   map[string]int
+	pairs      map[string]PairRule
+}
+
+// NewEngine builds an engine charging defaultBps on pairs without a rule.
+// Segments map a customer segment to basis points added to (or, when
+// negative, taken off) the pair spread.
+func NewEngine(defaultBps int, segments map[string]int, pairs []PairRule) (*Engine, error) {
+	if err := checkSpread("default spread", defaultBps); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		defaultBps: defaultBps,
+		segments:   make(map[string]int, len(segments)),
+		pairs:      make(map[string]PairRule, len(pairs)),
+	}
+	for name, bps := range segments {
+		e.segments[strings.ToLower(strings.TrimSpace(name))] = bps
+	}
+	for _, rule := range pairs {
+		rule.From = strings.ToUpper(strings.TrimSpace(rule.From))
+		rule.To = strings.ToUpper(strings.TrimSpace(rule.To))
+		key := pairKey(rule.From, rule.To)
+		if _, dup := e.pairs[key]; dup {
+			return nil, fmt.Errorf("duplicate pricing rule for %s", key)
+		}
+		if err := checkSpread("spread for "+key, rule.SpreadBps); err != nil {
+			return nil, err
+		}
+
+		rule.Tiers = append([]Tier(nil), rule.Tiers...)
+		for _, tier := range rule.Tiers {
+			if err := checkSpread("tier spread for "+key, tier.SpreadBps); err != nil {
+				return nil, err
+			}
+		}
+		sort.Slice(rule.Tiers, func(i, j int) bool {
+			return rule.Tiers[i].MinAmount.Cmp(rule.Tiers[j].MinAmount) < 0
+		})
+		e.pairs[key] = rule
+	}
+	return e, nil
+}
+
+func checkSpread(what string, bps int) error {
+	if bps < 0 || bps > maxSpreadBps {
+		return fmt.Errorf("%s must be between 0 and %d bps, got %d", what, maxSpreadBps, bps)
+	}
+	return nil
+}
+
+func pairKey(from, to string) string {
+	return from + "/" + to
+}
+
+// HasSegment reports whether segment is configured. The empty segment
+// always exists and adds nothing.
+func (e *Engine) HasSegment(segment string) bool {
+	if segment == "" {
+		return true
+	}
+	_, ok := e.segments[strings.ToLower(segment)]
+	return ok
+}
+
+// SpreadBps returns the per-side spread for converting amount of from into
+// to for a customer in segment, clamped to a valid range.
+func (e *Engine) SpreadBps(from, to string, amount domain.Money, segment string) int {
+	bps := e.defaultBps
+	if rule, ok := e.pairs[pairKey(from, to)]; ok {
+		bps = rule.SpreadBps
+		for _, tier := range rule.Tiers {
+			if amount.Cmp(tier.MinAmount) >= 0 {
+				bps = tier.SpreadBps
+			}
+		}
+	}
+	bps += e.segments[strings.ToLower(segment)]
+
+	if bps < 0 {
+		return 0
+	}
+	if bps > maxSpreadBps {
+		return maxSpreadBps
+	}
+	return bps
+}
+
+// Price applies the spread for the conversion to mid. Bid is rounded down
+// and Ask up, so rounding never favours the customer.
+func (e *Engine) Price(mid domain.Money, from, to string, amount domain.Money, segment string) domain.PricedRate {
+	bps := e.SpreadBps(from, to, amount, segment)
+	scale := mid.Scale
+	if scale < domain.DefaultScale {
+		scale = domain.DefaultScale
+	}
+
+	return domain.PricedRate{
+		Mid:       mid,
+		Bid:       applyBps(mid, 10000-bps, scale, domain.RoundFloor),
+		Ask:       applyBps(mid, 10000+bps, scale, domain.RoundCeiling),
+		SpreadBps: bps,
+		Segment:   segment,
+	}
+}
+
+func applyBps(rate domain.Money, factor, scale int, mode domain.RoundingMode) domain.Money {
+	return rate.MultiplyExact(domain.Money{Amount: big.NewInt(int64(factor))}).Quo(bpsDivisor, scale, mode)
+}
@@ -0,0 +1,135 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// Side is the currency a fee is denominated in.
+type Side string
+
+const (
+	// SideSource fees are in the currency paid and charged on top of it.
+	SideSource Side = "source"
+	// SideTarget fees are in the currency received and deducted from it.
+	SideTarget Side = "target"
+)
+
+var hundred = domain.Money{Amount: big.NewInt(100)}
+
+// Fee is one line of a fee schedule: Fixed plus Percent (0.25 meaning
+// 0.25%) of the amount on Side, kept within Min and Max when they are set.
+// Fixed, Min and Max are in the Side currency. From and To restrict the
+// conversions it applies to; empty matches any currency.
+type Fee struct {
+	Name    string
+	From    string
+	To      string
+	Side    Side
+	Fixed   domain.Money
+	Percent domain.Money
+	Min     *domain.Money
+	Max     *domain.Money
+}
+
+// FeeSchedule evaluates fees on conversions. The zero value charges none.
+type FeeSchedule struct {
+	fees []Fee
+}
+
+// NewFeeSchedule builds a schedule charging every matching fee, in order.
+func NewFeeSchedule(fees []Fee) (*FeeSchedule, error) {
+	s := &FeeSchedule{fees: make([]Fee, 0, len(fees))}
+	for i, fee := range fees {
+		if fee.Name == "" {
+			return nil, fmt.Errorf("fee %d has no name", i)
+		}
+		if fee.Side != SideSource && fee.Side != SideTarget {
+			return nil, fmt.Errorf("fee %s: side must be %s or %s, got %q", fee.Name, SideSource, SideTarget, fee.Side)
+		}
+		if fee.Fixed.IsNegative() || fee.Percent.IsNegative() {
+			return nil, fmt.Errorf("fee %s: fixed and percent must not be negative", fee.Name)
+		}
+		if fee.Min != nil && fee.Max != nil && fee.Min.Cmp(*fee.Max) > 0 {
+			return nil, fmt.Errorf("fee %s: min exceeds max", fee.Name)
+		}
+		fee.From = strings.ToUpper(strings.TrimSpace(fee.From))
+		fee.To = strings.ToUpper(strings.TrimSpace(fee.To))
+		s.fees = append(s.fees, fee)
+	}
+	return s, nil
+}
+
+func (f Fee) matches(from, to string, side Side) bool {
+	return f.Side == side && (f.From == "" || f.From == from) && (f.To == "" || f.To == to)
+}
+
+// Apply returns the fees on side for converting from into to, where base
+// is the amount on that side. Each fee is rounded half up to the minor
+// units of its currency.
+func (s *FeeSchedule) Apply(from, to string, side Side, base domain.Money) []domain.FeeItem {
+	currency := from
+	if side == SideTarget {
+		currency = to
+	}
+
+	items := make([]domain.FeeItem, 0)
+	for _, fee := range s.fees {
+		if !fee.matches(from, to, side) {
+			continue
+		}
+
+		amount := fee.Fixed.Add(base.MultiplyExact(fee.Percent).Quo(hundred, domain.MaxScale, domain.RoundHalfUp))
+		if fee.Min != nil && amount.Cmp(*fee.Min) < 0 {
+			amount = *fee.Min
+		}
+		if fee.Max != nil && amount.Cmp(*fee.Max) > 0 {
+			amount = *fee.Max
+		}
+
+		items = append(items, domain.FeeItem{
+			Name:     fee.Name,
+			Side:     string(side),
+			Currency: currency,
+			Amount:   amount.ConvertToScale(domain.MinorUnits(currency), domain.RoundHalfUp),
+		})
+	}
+	return items
+}
+
+// maxGrossIterations bounds the search in GrossFor. Each step closes the
+// gap by the fees' percentage, so it converges in a few dozen steps unless
+// the percentages add up to 100% or more and no gross can cover them.
+const maxGrossIterations = 1000
+
+// GrossFor returns the smallest target amount whose target fees, computed
+// on it as for any conversion, leave net, together with those fees. net
+// must be in the minor units of to. ok is false when the fees grow as fast
+// as the amount and no gross covers them.
+func (s *FeeSchedule) GrossFor(from, to string, net domain.Money) (gross domain.Money, fees []domain.FeeItem, ok bool) {
+	// Fees never fall as the amount grows, so net plus the fees on the
+	// current guess is a lower bound on the answer; the guesses rise until
+	// they meet it.
+	gross = net
+	for i := 0; i < maxGrossIterations; i++ {
+		fees = s.Apply(from, to, SideTarget, gross)
+		next := net.Add(Total(fees, net.Scale))
+		if next.Cmp(gross) == 0 {
+			return gross, fees, true
+		}
+		gross = next
+	}
+	return domain.Money{}, nil, false
+}
+
+// Total adds up the amounts of items, which must share a currency.
+func Total(items []domain.FeeItem, scale int) domain.Money {
+	total := domain.Money{Amount: new(big.Int), Scale: scale}
+	for _, item := range items {
+		total = total.Add(item.Amount)
+	}
+	return total
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/candles"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
 	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
@@ -13,14 +14,19 @@ import (
 type Scheduler struct {
 	conversionService service.ConversionService
 	cache             cache.Cache
+	candles           *candles.Store
 	baseUpdateTicker  *time.Ticker
 	adjUpdateTicker   *time.Ticker
 }
 
-func NewScheduler(svc service.ConversionService, c cache.Cache) *Scheduler {
+// NewScheduler returns a scheduler that refreshes svc's rates into c and
+// records the rates observed on every adjustment pass into store, which
+// may be nil.
+func NewScheduler(svc service.ConversionService, c cache.Cache, store *candles.Store) *Scheduler {
 	return &Scheduler{
 		conversionService: svc,
 		cache:             c,
+		candles:           store,
 	}
 }
 
@@ -55,7 +61,7 @@ func (s *Scheduler) updateBaseRates(ctx context.Context) {
 	base := "USD"
 	rates := make(map[string]domain.Money)
 
-	for _, target := range domain.SupportedCurrencies {
+	for _, target := range domain.EnabledCurrencies() {
 		if target.Code == base {
 			continue
 		}
@@ -72,6 +78,14 @@ func (s *Scheduler) updateBaseRates(ctx context.Context) {
 		log.Printf("Updated base rate %s: %s", key, rate.String())
 	}
 
+	now := time.Now()
+	s.cache.SetWithTTL(service.RateSnapshotKey, &domain.RateCache{
+		BaseRates:   rates,
+		Adjustments: make(map[string]domain.Money),
+		LastFetch:   now,
+		LastUpdate:  now,
+	}, 1*time.Hour)
+
 	s.updateCrossCurrencyRates(ctx, rates, base)
 	log.Printf("Base rates updated successfully - %d rates cached", len(rates))
 }
@@ -81,8 +95,10 @@ func (s *Scheduler) updateAdjustmentRates(ctx context.Context) {
 
 	adjustmentCount := 0
 	base := "USD"
+	adjustments := make(map[string]domain.Money)
+	observed := make(map[string]domain.Money)
 
-	for _, target := range domain.SupportedCurrencies {
+	for _, target := range domain.EnabledCurrencies() {
 		if target.Code == base {
 			continue
 		}
@@ -94,6 +110,7 @@ func (s *Scheduler) updateAdjustmentRates(ctx context.Context) {
 		}
 
 		key := base + ":" + target.Code
+		observed[key] = currentRate
 		cachedBaseRate, ok := s.cache.Get("base_rate_" + key)
 		if !ok {
 			continue
@@ -108,8 +125,9 @@ func (s *Scheduler) updateAdjustmentRates(ctx context.Context) {
 
 		// Only cache if adjustment is significant (> 0.01% threshold)
 		threshold := baseRate.MultiplyByFloat(0.0001)
-		if adjustment.Amount > threshold.Amount || adjustment.Amount < -threshold.Amount {
+		if adjustment.Abs().Cmp(threshold.Abs()) > 0 {
 			s.cache.SetWithTTL("adj_rate_"+key, adjustment, 5*time.Minute)
+			adjustments[key] = adjustment
 			adjustmentCount++
 
 			log.Printf("Updated adjustment for %s: %s (base: %s, current: %s)",
@@ -117,6 +135,9 @@ func (s *Scheduler) updateAdjustmentRates(ctx context.Context) {
 		}
 	}
 
+	s.recordRates(observed, time.Now())
+	s.publishAdjustments(adjustments)
+
 	if adjustmentCount > 0 {
 		log.Printf("Adjustment rates updated - %d adjustments cached", adjustmentCount)
 	} else {
@@ -124,6 +145,37 @@ func (s *Scheduler) updateAdjustmentRates(ctx context.Context) {
 	}
 }
 
+// recordRates keeps an observation for the candle store, if there is one.
+// Only updateAdjustmentRates calls it: it runs every five minutes, right
+// after each base refresh at startup too, so recording base rates as well
+// would count the same market twice.
+func (s *Scheduler) recordRates(rates map[string]domain.Money, at time.Time) {
+	if s.candles != nil {
+		s.candles.Record(rates, at)
+	}
+}
+
+// publishAdjustments replaces the snapshot published by updateBaseRates
+// with a copy carrying the new adjustments, so readers never observe a
+// half-applied refresh.
+func (s *Scheduler) publishAdjustments(adjustments map[string]domain.Money) {
+	cached, ok := s.cache.Get(service.RateSnapshotKey)
+	if !ok {
+		return
+	}
+	snapshot, ok := cached.(*domain.RateCache)
+	if !ok {
+		return
+	}
+
+	s.cache.SetWithTTL(service.RateSnapshotKey, &domain.RateCache{
+		BaseRates:   snapshot.BaseRates,
+		Adjustments: adjustments,
+		LastFetch:   snapshot.LastFetch,
+		LastUpdate:  time.Now(),
+	}, time.Until(snapshot.LastFetch.Add(1*time.Hour)))
+}
+
 func (s *Scheduler) updateCrossCurrencyRates(ctx context.Context, rates map[string]domain.Money, baseCurrency string) {
 	log.Println("Calculating cross-currency rates...")
 
@@ -199,7 +251,7 @@ func (s *Scheduler) ValidateRates(ctx context.Context) error {
 	invalidCount := 0
 	totalCount := 0
 
-	for _, target := range domain.SupportedCurrencies {
+	for _, target := range domain.EnabledCurrencies() {
 		if target.Code == "USD" {
 			continue
 		}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log/level"
+)
+
+type resolvedRate struct {
+	quoted quotedRate
+	err    error
+}
+
+// ConvertBatch converts every request independently, so one bad item does
+// not fail the others. Each distinct pair and rate date is resolved once.
+func (s *conversionService) ConvertBatch(ctx context.Context, reqs []domain.ConversionRequest) (*domain.BatchConversionResponse, error) {
+	if len(reqs) == 0 {
+		return nil, domain.NewError(domain.ErrInvalidRequest, "batch must contain at least one conversion", nil)
+	}
+	if len(reqs) > s.maxBatchSize {
+		return nil, domain.NewError(domain.ErrBatchTooLarge, "", map[string]interface{}{
+			"size":           len(reqs),
+			"max_batch_size": s.maxBatchSize,
+		})
+	}
+
+	level.Info(s.logger).Log("msg", "converting batch", "size", len(reqs))
+
+	resp := &domain.BatchConversionResponse{Items: make([]domain.BatchConversionItem, len(reqs))}
+	rates := make(map[string]resolvedRate)
+	for i := range reqs {
+		item := domain.BatchConversionItem{Index: i}
+		result, err := s.convertBatchItem(ctx, &reqs[i], rates)
+		if err != nil {
+			item.Error = asDomainError(err)
+			resp.Failed++
+		} else {
+			item.Result = result
+			resp.Succeeded++
+		}
+		resp.Items[i] = item
+	}
+	resp.Success = resp.Failed == 0
+
+	level.Info(s.logger).Log(
+		"msg", "batch completed",
+		"succeeded", resp.Succeeded,
+		"failed", resp.Failed,
+		"pairs_resolved", len(rates),
+	)
+	return resp, nil
+}
+
+func (s *conversionService) convertBatchItem(ctx context.Context, req *domain.ConversionRequest, rates map[string]resolvedRate) (*domain.ConversionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	historical := req.IsHistorical()
+	if req.Date.IsZero() {
+		req.Date = time.Now().UTC()
+	}
+
+	key := req.From + ":" + req.To
+	if historical {
+		key += ":" + req.Date.Format("2006-01-02")
+	}
+	resolved, ok := rates[key]
+	if !ok {
+		quoted, err := s.resolveRate(ctx, req.From, req.To, req.Date, historical)
+		resolved = resolvedRate{quoted: quoted, err: err}
+		rates[key] = resolved
+	}
+	if resolved.err != nil {
+		return nil, resolved.err
+	}
+	priced, err := s.priceFor(req, resolved.quoted)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertAt(req, priced, historical)
+}
+
+// asDomainError keeps typed errors as they are and reports anything else
+// as an internal error.
+func asDomainError(err error) *domain.Error {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return domain.WrapError(domain.ErrInternal, err, nil)
+}
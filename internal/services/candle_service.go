@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// GetCandles returns the OHLC candles recorded for the pair. Cross pairs
+// are derived from the USD legs observed in the same refresh.
+func (s *conversionService) GetCandles(ctx context.Context, req *domain.CandleRequest) (*domain.CandleResponse, error) {
+	from, to, err := domain.NormalizeCurrencyPair(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if req.Resolution == "" {
+		req.Resolution = domain.Resolution1h
+	}
+
+	return &domain.CandleResponse{
+		Success:    true,
+		From:       from,
+		To:         to,
+		Resolution: req.Resolution,
+		Candles:    s.candles.Candles(from, to, req.Resolution),
+	}, nil
+}
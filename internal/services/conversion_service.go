@@ -2,31 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/candles"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/pricing"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 )
 
+// RateSnapshotKey is the cache key under which the scheduler publishes the
+// latest USD-based *domain.RateCache.
+const RateSnapshotKey = "rate_snapshot"
+
 type ConversionService interface {
 	ConvertCurrency(ctx context.Context, req *domain.ConversionRequest) (*domain.ConversionResponse, error)
 	GetExchangeRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error)
 	GetPrecisionRate(ctx context.Context, from, to string) (domain.Money, error)
+	GetPricedRate(ctx context.Context, from, to string, amount domain.Money, segment string) (*domain.PricedRate, error)
+	ListCurrencies(ctx context.Context, filter domain.CurrencyFilter) ([]domain.CurrencyInfo, error)
+	GetCurrency(ctx context.Context, code string) (domain.CurrencyInfo, error)
+	ConvertBatch(ctx context.Context, reqs []domain.ConversionRequest) (*domain.BatchConversionResponse, error)
+	ConvertMulti(ctx context.Context, req *domain.MultiConversionRequest) (*domain.MultiConversionResponse, error)
+	GetRateHistory(ctx context.Context, req *domain.RateHistoryRequest) (*domain.RateHistoryResponse, error)
+	GetRateStats(ctx context.Context, req *domain.RateHistoryRequest) (*domain.RateStatsResponse, error)
+	GetCandles(ctx context.Context, req *domain.CandleRequest) (*domain.CandleResponse, error)
+	GetPeriodAverage(ctx context.Context, req *domain.PeriodRateRequest) (*domain.PeriodAverageResponse, error)
+	GetClosingRate(ctx context.Context, req *domain.PeriodRateRequest) (*domain.ClosingRateResponse, error)
+	CreateQuote(ctx context.Context, req *domain.ConversionRequest) (*domain.QuoteResponse, error)
+	ExecuteQuote(ctx context.Context, id string) (*domain.QuoteExecutionResponse, error)
 }
 
 type conversionService struct {
-	logger    log.Logger
-	api       external.ExchangeRateAPI
-	cache     cache.Cache
-	rateCache *domain.RateCache
+	logger           log.Logger
+	api              external.ExchangeRateAPI
+	cache            cache.Cache
+	rateCache        *domain.RateCache
+	maxBatchSize     int
+	maxHistoryPoints int
+	maxPeriodDays    int
+	candles          *candles.Store
+	quotes           QuoteStore
+	quoteTTL         time.Duration
+	pricing          *pricing.Engine
+	fees             *pricing.FeeSchedule
 }
 
-func NewConversionService(logger log.Logger, api external.ExchangeRateAPI, c cache.Cache) ConversionService {
-	return &conversionService{
+func NewConversionService(logger log.Logger, api external.ExchangeRateAPI, c cache.Cache, opts ...Option) ConversionService {
+	s := &conversionService{
 		logger: logger,
 		api:    api,
 		cache:  c,
@@ -34,7 +61,19 @@ func NewConversionService(logger log.Logger, api external.ExchangeRateAPI, c cac
 			BaseRates:   make(map[string]domain.Money),
 			Adjustments: make(map[string]domain.Money),
 		},
+		maxBatchSize:     DefaultMaxBatchSize,
+		maxHistoryPoints: DefaultMaxHistoryPoints,
+		maxPeriodDays:    DefaultMaxPeriodDays,
+		candles:          candles.NewStore("USD", candles.DefaultRetention),
+		quotes:           NewCacheQuoteStore(c),
+		quoteTTL:         DefaultQuoteTTL,
+		pricing:          &pricing.Engine{},
+		fees:             &pricing.FeeSchedule{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *conversionService) ConvertCurrency(ctx context.Context, req *domain.ConversionRequest) (*domain.ConversionResponse, error) {
@@ -42,42 +81,46 @@ func (s *conversionService) ConvertCurrency(ctx context.Context, req *domain.Con
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	historical := req.IsHistorical()
 	if req.Date.IsZero() {
 		req.Date = time.Now().UTC()
 	}
 
-	key := fmt.Sprintf("%s:%s:%d:%d:%s",
+	key := fmt.Sprintf("%s:%s:%d:%d:%s:%s:%s:%s",
 		req.From,
 		req.To,
 		req.Amount.Amount,
 		req.Amount.Scale,
-		req.Date.Format("2006-01-02"))
+		req.Date.Format("2006-01-02"),
+		req.Rounding,
+		req.FixedSide,
+		req.Segment)
 
 	cached, ok := s.cache.Get(key)
 	if ok {
 		level.Info(s.logger).Log("msg", "cache hit", "key", key)
 		resp, ok := cached.(*domain.ConversionResponse)
 		if ok {
+			if err := req.CheckSlippage(resp.Rate); err != nil {
+				return nil, err
+			}
 			return resp, nil
 		}
 	}
 
-	rate, err := s.GetPrecisionRate(ctx, req.From, req.To)
+	quoted, err := s.resolveRate(ctx, req.From, req.To, req.Date, historical)
 	if err != nil {
-		level.Error(s.logger).Log("msg", "failed to get precision rate", "error", err)
-		rate, err = s.GetExchangeRate(ctx, req.From, req.To, req.Date)
-		if err != nil {
-			level.Error(s.logger).Log("msg", "conversion failed", "error", err)
-			return nil, err
-		}
+		level.Error(s.logger).Log("msg", "conversion failed", "error", err)
+		return nil, err
 	}
 
-	result := req.Amount.Multiply(rate)
-
-	finalResp := &domain.ConversionResponse{
-		Success: true,
-		Result:  result,
-		Rate:    rate,
+	priced, err := s.priceFor(req, quoted)
+	if err != nil {
+		return nil, err
+	}
+	finalResp, err := s.convertAt(req, priced, historical)
+	if err != nil {
+		return nil, err
 	}
 
 	s.cache.Set(key, finalResp)
@@ -86,13 +129,179 @@ func (s *conversionService) ConvertCurrency(ctx context.Context, req *domain.Con
 		"from", req.From,
 		"to", req.To,
 		"amount", req.Amount.String(),
-		"rate", rate.String(),
-		"result", result.String(),
+		"rate", quoted.rate.String(),
+		"result", finalResp.Result.String(),
+		"rounding", req.Rounding.String(),
 	)
 	return finalResp, nil
 }
 
+// priceFor applies the customer spread for req to the quoted mid rate.
+// Tiers are picked on the amount in req.From, which for a fixed target is
+// the target's value at mid.
+func (s *conversionService) priceFor(req *domain.ConversionRequest, quoted quotedRate) (domain.PricedRate, error) {
+	if err := s.checkSegment(req.Segment); err != nil {
+		return domain.PricedRate{}, err
+	}
+	amount := req.Amount
+	if req.FixedSide == domain.FixedTo {
+		amount = req.Amount.Divide(quoted.rate)
+	}
+	return quoted.price(s.pricing.Price(quoted.rate, req.From, req.To, amount, req.Segment)), nil
+}
+
+func (s *conversionService) checkSegment(segment string) error {
+	if !s.pricing.HasSegment(segment) {
+		return domain.NewError(domain.ErrInvalidRequest, "unknown customer segment: "+segment, map[string]interface{}{"field": "segment"})
+	}
+	return nil
+}
+
+// convertAt applies an already priced rate to a validated request, unless
+// the customer rate is outside the request's slippage tolerance.
+func (s *conversionService) convertAt(req *domain.ConversionRequest, priced domain.PricedRate, historical bool) (*domain.ConversionResponse, error) {
+	if err := req.CheckSlippage(priced.Bid); err != nil {
+		level.Info(s.logger).Log("msg", "conversion rejected on slippage", "from", req.From, "to", req.To, "rate", priced.Bid.String(), "expected", req.ExpectedRate.String())
+		return nil, err
+	}
+	if req.FixedSide == domain.FixedTo {
+		return s.convertToTarget(req, priced, historical)
+	}
+
+	unrounded := req.Amount.Multiply(priced.Bid, req.Rounding)
+	result := unrounded.ConvertToScale(domain.MinorUnits(req.To), req.Rounding)
+	if err := unrounded.Validate(); err != nil {
+		level.Error(s.logger).Log("msg", "conversion result out of range", "error", err)
+		return nil, domain.WrapError(domain.ErrAmountOverflow, err, nil)
+	}
+
+	sourceFees := s.fees.Apply(req.From, req.To, pricing.SideSource, req.Amount)
+	targetFees := s.fees.Apply(req.From, req.To, pricing.SideTarget, result)
+	net := result.Subtract(pricing.Total(targetFees, result.Scale))
+	if net.IsNegative() {
+		return nil, domain.NewError(domain.ErrInvalidAmount, "amount does not cover the fees", map[string]interface{}{"field": "amount"})
+	}
+
+	return &domain.ConversionResponse{
+		Success:         true,
+		Result:          result,
+		UnroundedResult: unrounded,
+		Rate:            priced.Bid,
+		MidRate:         priced.Mid,
+		SpreadBps:       priced.SpreadBps,
+		Margin:          req.Amount.Multiply(priced.Mid).Subtract(result),
+		MarginCurrency:  req.To,
+		Fees:            append(sourceFees, targetFees...),
+		GrossAmount:     result,
+		NetAmount:       net,
+		TotalCharged:    req.Amount.Add(pricing.Total(sourceFees, req.Amount.Scale)).ConvertToScale(domain.MinorUnits(req.From), domain.RoundCeiling),
+		RateDate:        rateDate(priced, req.Date),
+		Historical:      historical,
+		Rounding:        req.Rounding,
+		FixedSide:       domain.FixedFrom,
+		Sources:         priced.Sources,
+	}, nil
+}
+
+// convertToTarget computes how much of req.From buys req.Amount of req.To
+// net of target fees. The fees are charged on the gross amount, as on a
+// forward conversion, so gross is the amount that leaves req.Amount once
+// its own fees are taken. Both the division
+// and the rounding to minor units go up, against the payer, whatever
+// rounding the request asked for: the charge must always cover the target.
+func (s *conversionService) convertToTarget(req *domain.ConversionRequest, priced domain.PricedRate, historical bool) (*domain.ConversionResponse, error) {
+	if priced.Bid.IsZero() {
+		return nil, domain.NewError(domain.ErrRateNotFound, "", map[string]interface{}{"from": req.From, "to": req.To})
+	}
+
+	net := req.Amount.ConvertToScale(domain.MinorUnits(req.To), domain.RoundCeiling)
+	gross, targetFees, ok := s.fees.GrossFor(req.From, req.To, net)
+	if !ok {
+		return nil, domain.NewError(domain.ErrInvalidAmount, "no amount covers the fees", map[string]interface{}{"field": "amount"})
+	}
+	unrounded := gross.Divide(priced.Bid, domain.RoundCeiling)
+	result := unrounded.ConvertToScale(domain.MinorUnits(req.From), domain.RoundCeiling)
+	if err := result.Validate(); err != nil {
+		level.Error(s.logger).Log("msg", "conversion result out of range", "error", err)
+		return nil, domain.WrapError(domain.ErrAmountOverflow, err, nil)
+	}
+	roundTrip := result.Multiply(priced.Bid)
+	sourceFees := s.fees.Apply(req.From, req.To, pricing.SideSource, result)
+
+	return &domain.ConversionResponse{
+		Success:         true,
+		Result:          result,
+		UnroundedResult: unrounded,
+		Rate:            priced.Bid,
+		MidRate:         priced.Mid,
+		SpreadBps:       priced.SpreadBps,
+		Margin:          result.Subtract(gross.Divide(priced.Mid)),
+		MarginCurrency:  req.From,
+		Fees:            append(sourceFees, targetFees...),
+		GrossAmount:     gross,
+		NetAmount:       net,
+		TotalCharged:    result.Add(pricing.Total(sourceFees, result.Scale)),
+		RateDate:        rateDate(priced, req.Date),
+		Historical:      historical,
+		Rounding:        domain.RoundCeiling,
+		FixedSide:       domain.FixedTo,
+		RoundTripAmount: &roundTrip,
+		Sources:         priced.Sources,
+	}, nil
+}
+
+// rateDate is the day of the rate a conversion used, or the requested
+// date when the provider did not say.
+func rateDate(priced domain.PricedRate, requested time.Time) string {
+	if priced.RateDate != "" {
+		return priced.RateDate
+	}
+	return requested.Format("2006-01-02")
+}
+
+// quotedRate is a mid rate with what the provider said about it: the
+// providers it came from and the day it was published for. Both are left
+// empty when the provider does not report them.
+type quotedRate struct {
+	rate    domain.Money
+	sources []string
+	date    time.Time
+}
+
+// price records the quote's provenance on priced.
+func (q quotedRate) price(priced domain.PricedRate) domain.PricedRate {
+	priced.Sources = q.sources
+	if !q.date.IsZero() {
+		priced.RateDate = q.date.UTC().Format("2006-01-02")
+	}
+	return priced
+}
+
+// resolveRate picks the rate for a conversion. Past dates always go to the
+// historical source: the cached live rates describe today and must not be
+// reused for them.
+func (s *conversionService) resolveRate(ctx context.Context, from, to string, date time.Time, historical bool) (quotedRate, error) {
+	if historical {
+		return s.exchangeRate(ctx, from, to, date)
+	}
+
+	quoted, err := s.precisionRate(ctx, from, to)
+	if err != nil {
+		level.Error(s.logger).Log("msg", "failed to get precision rate", "error", err)
+		return s.exchangeRate(ctx, from, to, date)
+	}
+	return quoted, nil
+}
+
 func (s *conversionService) GetExchangeRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
+	quoted, err := s.exchangeRate(ctx, from, to, date)
+	return quoted.rate, err
+}
+
+// exchangeRate asks the provider for from/to on date. A provider that
+// falls back to an earlier day, over a weekend or holiday, reports that
+// day in the quote.
+func (s *conversionService) exchangeRate(ctx context.Context, from, to string, date time.Time) (quotedRate, error) {
 	rateReq := domain.ExchangeRate{
 		From: from,
 		To:   to,
@@ -101,25 +310,108 @@ func (s *conversionService) GetExchangeRate(ctx context.Context, from, to string
 	}
 
 	resp, err := s.api.Convert(ctx, rateReq)
+	details := map[string]interface{}{"from": from, "to": to}
+	if errors.Is(err, domain.ErrRateNotFound) {
+		return quotedRate{}, domain.NewError(domain.ErrRateNotFound, "", details)
+	}
 	if err != nil {
-		return domain.Money{}, fmt.Errorf("failed to fetch exchange rate: %w", err)
+		return quotedRate{}, domain.WrapError(domain.ErrUpstreamUnavailable, err, details)
+	}
+	if resp.Rate.IsZero() {
+		return quotedRate{}, domain.NewError(domain.ErrRateNotFound, "", details)
+	}
+	quoted := quotedRate{rate: resp.Rate, sources: resp.Sources, date: resp.Timestamp}
+	// A provider may fall back to an earlier day but never to a later one;
+	// a later timestamp is when it answered, not what it priced.
+	if quoted.date.UTC().Format("2006-01-02") > date.UTC().Format("2006-01-02") {
+		quoted.date = time.Time{}
 	}
-	return resp.Rate, nil
+	return quoted, nil
 }
 
 func (s *conversionService) GetPrecisionRate(ctx context.Context, from, to string) (domain.Money, error) {
+	quoted, err := s.precisionRate(ctx, from, to)
+	return quoted.rate, err
+}
+
+// precisionRate is GetPrecisionRate with what the provider said about a
+// live rate; rates served from the cache carry only the rate.
+func (s *conversionService) precisionRate(ctx context.Context, from, to string) (quotedRate, error) {
 	rate := s.rateCache.GetPrecisionRate(from, to)
 	if !rate.IsZero() && !s.rateCache.IsStale(5*time.Minute) {
-		return rate, nil
+		return quotedRate{rate: rate}, nil
 	}
 
 	if from != "USD" && to != "USD" {
 		crossRate := s.rateCache.CrossRate(from, to, "USD")
 		if !crossRate.IsZero() {
-			return crossRate, nil
+			return quotedRate{rate: crossRate}, nil
 		}
 	}
-	return s.GetExchangeRate(ctx, from, to, time.Now().UTC())
+	return s.exchangeRate(ctx, from, to, time.Now().UTC())
+}
+
+// GetPricedRate returns the live mid rate with the customer spread for
+// converting amount of from (zero for the base tier) in segment.
+func (s *conversionService) GetPricedRate(ctx context.Context, from, to string, amount domain.Money, segment string) (*domain.PricedRate, error) {
+	if err := s.checkSegment(segment); err != nil {
+		return nil, err
+	}
+	quoted, err := s.precisionRate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	priced := quoted.price(s.pricing.Price(quoted.rate, from, to, amount, segment))
+	return &priced, nil
+}
+
+func (s *conversionService) ListCurrencies(ctx context.Context, filter domain.CurrencyFilter) ([]domain.CurrencyInfo, error) {
+	snapshot := s.rateSnapshot()
+	currencies := make([]domain.CurrencyInfo, 0)
+	for _, currency := range domain.EnabledCurrencies() {
+		if filter.Matches(currency) {
+			currencies = append(currencies, s.currencyInfo(currency, snapshot))
+		}
+	}
+	return currencies, nil
+}
+
+func (s *conversionService) GetCurrency(ctx context.Context, code string) (domain.CurrencyInfo, error) {
+	currency, ok := domain.GetCurrency(code)
+	if !ok || !domain.IsValidCurrency(currency.Code) {
+		return domain.CurrencyInfo{}, domain.NewError(domain.ErrCurrencyNotFound, "currency not found: "+code, map[string]interface{}{"currency": code})
+	}
+	return s.currencyInfo(currency, s.rateSnapshot()), nil
+}
+
+func (s *conversionService) currencyInfo(currency domain.Currency, snapshot *domain.RateCache) domain.CurrencyInfo {
+	info := domain.CurrencyInfo{Currency: currency}
+	if snapshot == nil || currency.Code == "USD" {
+		return info
+	}
+
+	rate := snapshot.GetPrecisionRate("USD", currency.Code)
+	if rate.IsZero() {
+		return info
+	}
+	info.LatestRate = &domain.RateFreshness{
+		Base:       "USD",
+		Rate:       rate,
+		UpdatedAt:  snapshot.LastUpdate,
+		AgeSeconds: int64(time.Since(snapshot.LastUpdate).Seconds()),
+	}
+	return info
+}
+
+// rateSnapshot returns the rates last published by the scheduler, or nil
+// before the first refresh.
+func (s *conversionService) rateSnapshot() *domain.RateCache {
+	cached, ok := s.cache.Get(RateSnapshotKey)
+	if !ok {
+		return nil
+	}
+	snapshot, _ := cached.(*domain.RateCache)
+	return snapshot
 }
 
 func (s *conversionService) UpdateRateCache(ctx context.Context) error {
@@ -128,7 +420,7 @@ func (s *conversionService) UpdateRateCache(ctx context.Context) error {
 	base := "USD"
 	newRates := make(map[string]domain.Money)
 
-	for _, target := range domain.SupportedCurrencies {
+	for _, target := range domain.EnabledCurrencies() {
 		if target.Code == base {
 			continue
 		}
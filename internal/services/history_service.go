@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log/level"
+)
+
+// GetRateHistory returns the pair's rate on every sampled date. Dates the
+// provider cannot price stay in the series as gaps carrying their error.
+// Past days are cached: their rates no longer change.
+func (s *conversionService) GetRateHistory(ctx context.Context, req *domain.RateHistoryRequest) (*domain.RateHistoryResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	dates := req.Dates()
+	if len(dates) > s.maxHistoryPoints {
+		return nil, domain.NewError(domain.ErrDateOutOfRange, "date range has too many points", map[string]interface{}{
+			"points":             len(dates),
+			"max_history_points": s.maxHistoryPoints,
+		})
+	}
+
+	level.Info(s.logger).Log("msg", "fetching rate history", "from", req.From, "to", req.To, "points", len(dates), "interval", req.Interval)
+
+	resp := &domain.RateHistoryResponse{
+		From:     req.From,
+		To:       req.To,
+		Start:    req.Start.Format("2006-01-02"),
+		End:      req.End.Format("2006-01-02"),
+		Interval: req.Interval,
+		Points:   make([]domain.RatePoint, len(dates)),
+	}
+	for i, date := range dates {
+		resp.Points[i] = s.historyPoint(ctx, req.From, req.To, date)
+		if resp.Points[i].Rate == nil {
+			resp.Gaps++
+		}
+	}
+	resp.Success = resp.Gaps == 0
+	return resp, nil
+}
+
+// historyPoint prices one day of a series, leaving the error on the point
+// when the provider has no rate for it.
+func (s *conversionService) historyPoint(ctx context.Context, from, to string, date time.Time) domain.RatePoint {
+	day := date.Format("2006-01-02")
+	point := domain.RatePoint{Date: day}
+
+	key := fmt.Sprintf("history:%s:%s:%s", from, to, day)
+	if cached, ok := s.cache.Get(key); ok {
+		if rate, ok := cached.(domain.Money); ok {
+			point.Rate = &rate
+			return point
+		}
+	}
+
+	rate, err := s.GetExchangeRate(ctx, from, to, date)
+	if err != nil {
+		level.Error(s.logger).Log("msg", "missing history point", "date", day, "error", err)
+		point.Error = asDomainError(err)
+		return point
+	}
+	point.Rate = &rate
+	if date.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		s.cache.Set(key, rate)
+	}
+	return point
+}
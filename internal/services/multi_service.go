@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log/level"
+)
+
+// ConvertMulti fans one amount out to several currencies. Every target is
+// priced through CrossRate on a single USD-based snapshot, so results can
+// never mix rates from either side of a scheduler refresh.
+func (s *conversionService) ConvertMulti(ctx context.Context, req *domain.MultiConversionRequest) (*domain.MultiConversionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if len(req.To) > s.maxBatchSize {
+		return nil, domain.NewError(domain.ErrBatchTooLarge, "", map[string]interface{}{
+			"size":           len(req.To),
+			"max_batch_size": s.maxBatchSize,
+		})
+	}
+
+	level.Info(s.logger).Log("msg", "converting to multiple currencies", "from", req.From, "targets", len(req.To))
+
+	codes := append([]string{req.From}, req.To...)
+	snapshot, err := s.snapshotFor(ctx, codes)
+	if err != nil {
+		level.Error(s.logger).Log("msg", "multi conversion failed", "error", err)
+		return nil, err
+	}
+
+	if err := s.checkSegment(req.Segment); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.MultiConversionResult, 0, len(req.To))
+	for _, to := range req.To {
+		priced := s.pricing.Price(snapshot.CrossRate(req.From, to, "USD"), req.From, to, req.Amount, req.Segment)
+		unrounded := req.Amount.Multiply(priced.Bid, req.Rounding)
+		if err := unrounded.Validate(); err != nil {
+			return nil, domain.WrapError(domain.ErrAmountOverflow, err, map[string]interface{}{"to": to})
+		}
+		results = append(results, domain.MultiConversionResult{
+			To:              to,
+			Result:          unrounded.ConvertToScale(domain.MinorUnits(to), req.Rounding),
+			UnroundedResult: unrounded,
+			Rate:            priced.Bid,
+			MidRate:         priced.Mid,
+			SpreadBps:       priced.SpreadBps,
+		})
+	}
+
+	return &domain.MultiConversionResponse{
+		Success:   true,
+		From:      req.From,
+		Amount:    req.Amount,
+		Base:      "USD",
+		RatesAsOf: snapshot.LastUpdate,
+		Rounding:  req.Rounding,
+		Results:   results,
+	}, nil
+}
+
+// snapshotFor returns a rate snapshot covering every code. The scheduler's
+// snapshot is used when it has them all; otherwise the USD legs are fetched
+// afresh into a private snapshot, never patched into the shared one.
+func (s *conversionService) snapshotFor(ctx context.Context, codes []string) (*domain.RateCache, error) {
+	if snapshot := s.rateSnapshot(); snapshot != nil && snapshotCovers(snapshot, codes) {
+		return snapshot, nil
+	}
+
+	now := time.Now()
+	snapshot := &domain.RateCache{
+		BaseRates:   make(map[string]domain.Money, len(codes)),
+		Adjustments: make(map[string]domain.Money),
+		LastFetch:   now,
+		LastUpdate:  now,
+	}
+	for _, code := range codes {
+		if code == "USD" {
+			continue
+		}
+		rate, err := s.GetExchangeRate(ctx, "USD", code, now.UTC())
+		if err != nil {
+			return nil, err
+		}
+		snapshot.BaseRates["USD:"+code] = rate
+	}
+	return snapshot, nil
+}
+
+func snapshotCovers(snapshot *domain.RateCache, codes []string) bool {
+	for _, code := range codes {
+		if !snapshot.Has("USD", code) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/candles"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/pricing"
+)
+
+const (
+	// DefaultMaxBatchSize caps ConvertBatch when no limit is configured.
+	DefaultMaxBatchSize = 100
+	// DefaultMaxHistoryPoints caps GetRateHistory when no limit is
+	// configured: a month of daily rates.
+	DefaultMaxHistoryPoints = 31
+	// DefaultMaxPeriodDays caps the days GetPeriodAverage fetches when no
+	// limit is configured: a calendar quarter with weekends included.
+	DefaultMaxPeriodDays = 92
+	// DefaultQuoteTTL is how long a quote's rate is honoured when no TTL
+	// is configured.
+	DefaultQuoteTTL = 5 * time.Minute
+)
+
+// Option configures optional behaviour of the conversion service.
+type Option func(*conversionService)
+
+// WithMaxBatchSize limits how many conversions one ConvertBatch call may
+// carry. Non-positive values keep the default.
+func WithMaxBatchSize(n int) Option {
+	return func(s *conversionService) {
+		if n > 0 {
+			s.maxBatchSize = n
+		}
+	}
+}
+
+// WithMaxHistoryPoints limits how many dates one GetRateHistory call may
+// sample; each point can cost an upstream request. Non-positive values
+// keep the default.
+func WithMaxHistoryPoints(n int) Option {
+	return func(s *conversionService) {
+		if n > 0 {
+			s.maxHistoryPoints = n
+		}
+	}
+}
+
+// WithMaxPeriodDays limits how many days one GetPeriodAverage call may
+// fetch. Non-positive values keep the default.
+func WithMaxPeriodDays(n int) Option {
+	return func(s *conversionService) {
+		if n > 0 {
+			s.maxPeriodDays = n
+		}
+	}
+}
+
+// WithCandleStore serves candles from store, which the scheduler records
+// into. Without it the service keeps an empty store of its own.
+func WithCandleStore(store *candles.Store) Option {
+	return func(s *conversionService) {
+		if store != nil {
+			s.candles = store
+		}
+	}
+}
+
+// WithQuoteStore keeps quotes in store instead of the service cache.
+func WithQuoteStore(store QuoteStore) Option {
+	return func(s *conversionService) {
+		if store != nil {
+			s.quotes = store
+		}
+	}
+}
+
+// WithQuoteTTL sets how long a quote stays executable. Non-positive values
+// keep the default.
+func WithQuoteTTL(ttl time.Duration) Option {
+	return func(s *conversionService) {
+		if ttl > 0 {
+			s.quoteTTL = ttl
+		}
+	}
+}
+
+// WithPricing charges the spreads of engine on every conversion. Without
+// it customers get the mid rate.
+func WithPricing(engine *pricing.Engine) Option {
+	return func(s *conversionService) {
+		if engine != nil {
+			s.pricing = engine
+		}
+	}
+}
+
+// WithFees charges the fees of schedule on every conversion.
+func WithFees(schedule *pricing.FeeSchedule) Option {
+	return func(s *conversionService) {
+		if schedule != nil {
+			s.fees = schedule
+		}
+	}
+}
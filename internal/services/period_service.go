@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log/level"
+)
+
+// GetPeriodAverage returns the arithmetic mean of the daily rates from
+// req.Start to req.End. Weekends follow req.Weekends; days the provider
+// cannot price are listed as missing and left out of the mean. Each day
+// can cost an upstream request, so at most maxPeriodDays are fetched.
+func (s *conversionService) GetPeriodAverage(ctx context.Context, req *domain.PeriodRateRequest) (*domain.PeriodAverageResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	for date := req.Start; !date.After(req.End); date = date.AddDate(0, 0, 1) {
+		if !req.Weekends.Skips(date) {
+			dates = append(dates, date)
+		}
+	}
+	if len(dates) > s.maxPeriodDays {
+		return nil, domain.NewError(domain.ErrDateOutOfRange, "period has too many days", map[string]interface{}{
+			"days":            len(dates),
+			"max_period_days": s.maxPeriodDays,
+		})
+	}
+
+	level.Info(s.logger).Log("msg", "computing period average", "from", req.From, "to", req.To, "start", req.Start.Format("2006-01-02"), "end", req.End.Format("2006-01-02"))
+
+	resp := &domain.PeriodAverageResponse{
+		From:         req.From,
+		To:           req.To,
+		Start:        req.Start.Format("2006-01-02"),
+		End:          req.End.Format("2006-01-02"),
+		Rounding:     req.Rounding,
+		Weekends:     req.Weekends,
+		RateDates:    make([]string, 0),
+		SkippedDates: make([]string, 0),
+		MissingDates: make([]string, 0),
+	}
+
+	var sum domain.Money
+	for date := req.Start; !date.After(req.End); date = date.AddDate(0, 0, 1) {
+		if req.Weekends.Skips(date) {
+			resp.SkippedDates = append(resp.SkippedDates, date.Format("2006-01-02"))
+			continue
+		}
+		point := s.historyPoint(ctx, req.From, req.To, date)
+		if point.Rate == nil {
+			resp.MissingDates = append(resp.MissingDates, point.Date)
+			continue
+		}
+		sum = sum.Add(*point.Rate)
+		resp.RateDates = append(resp.RateDates, point.Date)
+	}
+
+	if len(resp.RateDates) == 0 {
+		return nil, domain.NewError(domain.ErrRateNotFound, "no rates available for the requested period", map[string]interface{}{
+			"from":  req.From,
+			"to":    req.To,
+			"start": resp.Start,
+			"end":   resp.End,
+		})
+	}
+
+	count := domain.Money{Amount: big.NewInt(int64(len(resp.RateDates)))}
+	resp.Rate = sum.Quo(count, req.Scale, req.Rounding)
+	resp.Success = true
+	return resp, nil
+}
+
+// GetClosingRate returns the last rate available on or before req.End,
+// stepping back over weekends (per req.Weekends) and unpriced days for at
+// most MaxClosingLookbackDays.
+func (s *conversionService) GetClosingRate(ctx context.Context, req *domain.PeriodRateRequest) (*domain.ClosingRateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp := &domain.ClosingRateResponse{
+		From:         req.From,
+		To:           req.To,
+		PeriodEnd:    req.End.Format("2006-01-02"),
+		Rounding:     req.Rounding,
+		Weekends:     req.Weekends,
+		SkippedDates: make([]string, 0),
+	}
+
+	oldest := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -domain.MaxPeriodHistoryDays)
+	for back := 0; back <= domain.MaxClosingLookbackDays; back++ {
+		date := req.End.AddDate(0, 0, -back)
+		if date.Before(oldest) {
+			break
+		}
+		if !req.Weekends.Skips(date) {
+			point := s.historyPoint(ctx, req.From, req.To, date)
+			if point.Rate != nil {
+				resp.RateDate = point.Date
+				resp.Rate = point.Rate.ConvertToScale(req.Scale, req.Rounding)
+				resp.Success = true
+				return resp, nil
+			}
+		}
+		resp.SkippedDates = append(resp.SkippedDates, date.Format("2006-01-02"))
+	}
+
+	return nil, domain.NewError(domain.ErrRateNotFound, "no rate available on or before the period end", map[string]interface{}{
+		"from":          req.From,
+		"to":            req.To,
+		"period_end":    resp.PeriodEnd,
+		"skipped_dates": resp.SkippedDates,
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log/level"
+)
+
+// CreateQuote prices req at the live rate and locks that rate for the
+// quote TTL. Quotes are never issued for past dates.
+func (s *conversionService) CreateQuote(ctx context.Context, req *domain.ConversionRequest) (*domain.QuoteResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.IsHistorical() {
+		return nil, domain.NewError(domain.ErrInvalidDate, "quotes are only issued at the live rate", map[string]interface{}{"field": "date"})
+	}
+	req.Date = time.Now().UTC()
+
+	quoted, err := s.resolveRate(ctx, req.From, req.To, req.Date, false)
+	if err != nil {
+		level.Error(s.logger).Log("msg", "quote failed", "error", err)
+		return nil, err
+	}
+	priced, err := s.priceFor(req, quoted)
+	if err != nil {
+		return nil, err
+	}
+	conversion, err := s.convertAt(req, priced, false)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newQuoteID()
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternal, err, nil)
+	}
+	quote := domain.Quote{
+		ID:              id,
+		From:            req.From,
+		To:              req.To,
+		Amount:          req.Amount,
+		Rate:            priced.Bid,
+		MidRate:         priced.Mid,
+		SpreadBps:       priced.SpreadBps,
+		Segment:         req.Segment,
+		Result:          conversion.Result,
+		UnroundedResult: conversion.UnroundedResult,
+		Rounding:        conversion.Rounding,
+		FixedSide:       req.FixedSide,
+		CreatedAt:       req.Date,
+		ExpiresAt:       req.Date.Add(s.quoteTTL),
+	}
+	if err := s.quotes.Save(ctx, quote); err != nil {
+		return nil, domain.WrapError(domain.ErrInternal, err, nil)
+	}
+
+	level.Info(s.logger).Log("msg", "quote issued", "id", id, "from", req.From, "to", req.To, "rate", priced.Bid.String(), "expires_at", quote.ExpiresAt)
+	return &domain.QuoteResponse{Success: true, Quote: quote}, nil
+}
+
+// ExecuteQuote converts at the rate locked by the quote, whatever the
+// market has done since. A quote executes at most once.
+func (s *conversionService) ExecuteQuote(ctx context.Context, id string) (*domain.QuoteExecutionResponse, error) {
+	details := map[string]interface{}{"quote_id": id}
+	now := time.Now().UTC()
+	quote, ok, err := s.quotes.Take(ctx, id, now)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternal, err, details)
+	}
+	if !ok {
+		return nil, domain.NewError(domain.ErrQuoteNotFound, "", details)
+	}
+
+	if quote.Expired(now) {
+		details["expired_at"] = quote.ExpiresAt
+		return nil, domain.NewError(domain.ErrQuoteExpired, "", details)
+	}
+
+	req := &domain.ConversionRequest{
+		From:      quote.From,
+		To:        quote.To,
+		Amount:    quote.Amount,
+		Date:      quote.CreatedAt,
+		Rounding:  quote.Rounding,
+		FixedSide: quote.FixedSide,
+		Segment:   quote.Segment,
+	}
+	priced := domain.PricedRate{Mid: quote.MidRate, Bid: quote.Rate, SpreadBps: quote.SpreadBps, Segment: quote.Segment}
+	conversion, err := s.convertAt(req, priced, false)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Info(s.logger).Log("msg", "quote executed", "id", id, "rate", quote.Rate.String(), "result", conversion.Result.String())
+	return &domain.QuoteExecutionResponse{
+		Success:    true,
+		QuoteID:    id,
+		ExecutedAt: now,
+		Conversion: *conversion,
+	}, nil
+}
+
+func newQuoteID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+)
+
+// QuoteStore keeps issued quotes. Implementations must keep a quote for a
+// while past its expiry so that late executions can be told it expired
+// rather than that it never existed.
+type QuoteStore interface {
+	Save(ctx context.Context, quote domain.Quote) error
+	// Take returns the quote with the given ID and removes it unless it
+	// has expired at now, so that a quote is handed out for execution at
+	// most once and every late attempt finds it expired.
+	Take(ctx context.Context, id string, now time.Time) (domain.Quote, bool, error)
+}
+
+// expiredQuoteRetention is how long an expired quote stays in a
+// cacheQuoteStore.
+const expiredQuoteRetention = time.Hour
+
+type cacheQuoteStore struct {
+	mu    sync.Mutex
+	cache cache.Cache
+}
+
+// NewCacheQuoteStore returns a QuoteStore on top of c, relying on its TTL
+// support to forget quotes an hour after they expire.
+func NewCacheQuoteStore(c cache.Cache) QuoteStore {
+	return &cacheQuoteStore{cache: c}
+}
+
+func (s *cacheQuoteStore) Save(_ context.Context, quote domain.Quote) error {
+	s.cache.SetWithTTL(quoteKey(quote.ID), quote, time.Until(quote.ExpiresAt)+expiredQuoteRetention)
+	return nil
+}
+
+func (s *cacheQuoteStore) Take(_ context.Context, id string, now time.Time) (domain.Quote, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.cache.Get(quoteKey(id))
+	if !ok {
+		return domain.Quote{}, false, nil
+	}
+	quote, ok := cached.(domain.Quote)
+	if ok && !quote.Expired(now) {
+		s.cache.Delete(quoteKey(id))
+	}
+	return quote, ok, nil
+}
+
+func quoteKey(id string) string {
+	return "quote_" + id
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// GetRateStats summarises the pair's rate history over the requested
+// period. Gaps in the series are skipped; a period with no priced date at
+// all is reported as rate_not_found.
+func (s *conversionService) GetRateStats(ctx context.Context, req *domain.RateHistoryRequest) (*domain.RateStatsResponse, error) {
+	history, err := s.GetRateHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &domain.RateStatsResponse{
+		From:     history.From,
+		To:       history.To,
+		Start:    history.Start,
+		End:      history.End,
+		Interval: history.Interval,
+	}
+	if !domain.SummarizeRates(history, resp) {
+		return nil, domain.NewError(domain.ErrRateNotFound, "no rates available for the requested period", map[string]interface{}{
+			"from":  history.From,
+			"to":    history.To,
+			"start": history.Start,
+			"end":   history.End,
+		})
+	}
+	resp.Success = true
+	return resp, nil
+}
@@ -19,9 +19,113 @@ type Config struct {
 		Timeout time.Duration `yaml:"timeout"`
 	} `yaml:"external_api"`
 
+	Providers struct {
+		Cooldown time.Duration    `yaml:"cooldown"`
+		Sources  []ProviderConfig `yaml:"sources"`
+	} `yaml:"providers"`
+
 	Cache struct {
 		TTL int `yaml:"ttl"`
 	} `yaml:"cache"`
+
+	Limits struct {
+		MaxBatchSize     int `yaml:"max_batch_size"`
+		MaxHistoryPoints int `yaml:"max_history_points"`
+		MaxPeriodDays    int `yaml:"max_period_days"`
+	} `yaml:"limits"`
+
+	Quotes struct {
+		TTL time.Duration `yaml:"ttl"`
+	} `yaml:"quotes"`
+
+	Pricing struct {
+		DefaultSpreadBps int            `yaml:"default_spread_bps"`
+		Segments         map[string]int `yaml:"segments"`
+		Pairs            []PricingPair  `yaml:"pairs"`
+	} `yaml:"pricing"`
+
+	Fees []FeeConfig `yaml:"fees"`
+
+	Candles struct {
+		Retention time.Duration `yaml:"retention"`
+	} `yaml:"candles"`
+
+	Currencies struct {
+		Enabled       []string `yaml:"enabled"`
+		Disabled      []string `yaml:"disabled"`
+		OverridesFile string   `yaml:"overrides_file"`
+	} `yaml:"currencies"`
+}
+
+// ProviderConfig is one rate source. Sources are tried in the order they
+// are listed; Type selects the implementation. BaseURL and APIKey
+// configure exchangerate_host; Source and HistorySource are the URL or
+// file path of an ecb feed. A static source is a CSV or JSON rates file,
+// checked for changes every PollInterval; dated lookups fall back to the
+// nearest previous date at most FallbackDays earlier. A consensus source
+// asks all its Members at once and combines their rates by Method, after
+// dropping those more than MaxDeviationBps from the median.
+type ProviderConfig struct {
+	Name          string        `yaml:"name"`
+	Type          string        `yaml:"type"`
+	BaseURL       string        `yaml:"base_url"`
+	APIKey        string        `yaml:"api_key"`
+	Source        string        `yaml:"source"`
+	HistorySource string        `yaml:"history_source"`
+	FallbackDays  int           `yaml:"fallback_days"`
+	PollInterval  time.Duration `yaml:"poll_interval"`
+	Timeout       time.Duration `yaml:"timeout"`
+	CallTimeout   time.Duration `yaml:"call_timeout"`
+
+	Method          string           `yaml:"method"`
+	TrimPercent     int              `yaml:"trim_percent"`
+	MaxDeviationBps int              `yaml:"max_deviation_bps"`
+	MinSources      int              `yaml:"min_sources"`
+	Members         []ProviderConfig `yaml:"members"`
+
+	Retry RetryConfig `yaml:"retry"`
+}
+
+// RetryConfig overrides the default retry policy of an exchangerate_host
+// source; fields left unset keep their default. RetryOn lists the error
+// classes to retry: timeout, network and status.
+type RetryConfig struct {
+	MaxAttempts     int           `yaml:"max_attempts"`
+	BaseDelay       time.Duration `yaml:"base_delay"`
+	MaxDelay        time.Duration `yaml:"max_delay"`
+	Jitter          *float64      `yaml:"jitter"`
+	RetryableStatus []int         `yaml:"retryable_status"`
+	RetryOn         []string      `yaml:"retry_on"`
+}
+
+// PricingPair is the spread for converting From into To, optionally
+// lowered for larger amounts by tiers.
+type PricingPair struct {
+	From      string        `yaml:"from"`
+	To        string        `yaml:"to"`
+	SpreadBps int           `yaml:"spread_bps"`
+	Tiers     []PricingTier `yaml:"tiers"`
+}
+
+// PricingTier applies SpreadBps from MinAmount, a decimal in the pair's
+// From currency.
+type PricingTier struct {
+	MinAmount string `yaml:"min_amount"`
+	SpreadBps int    `yaml:"spread_bps"`
+}
+
+// FeeConfig is one fee charged on conversions from From into To (empty
+// matches any currency), in the source or target currency per Side.
+// Amounts are decimals; Percent "0.25" means 0.25%.
+type FeeConfig struct {
+	Name    string `yaml:"name"`
+	From    string `yaml:"from"`
+	To      string `yaml:"to"`
+	Side    string `yaml:"side"`
+	Fixed   string `yaml:"fixed"`
+	Percent string `yaml:"percent"`
+	Min     string `yaml:"min"`
+	Max     string `yaml:"max"`
 }
 
 func Load(path string) (*Config, error) {
@@ -10,18 +10,40 @@ import (
 )
 
 type ConversionEndpoints struct {
-	Convert       endpoint.Endpoint
-	GetRate       endpoint.Endpoint
-	Health        endpoint.Endpoint
-	PrecisionInfo endpoint.Endpoint
+	Convert        endpoint.Endpoint
+	GetRate        endpoint.Endpoint
+	Health         endpoint.Endpoint
+	PrecisionInfo  endpoint.Endpoint
+	ListCurrencies endpoint.Endpoint
+	GetCurrency    endpoint.Endpoint
+	ConvertBatch   endpoint.Endpoint
+	ConvertMulti   endpoint.Endpoint
+	RateHistory    endpoint.Endpoint
+	RateStats      endpoint.Endpoint
+	Candles        endpoint.Endpoint
+	PeriodAverage  endpoint.Endpoint
+	ClosingRate    endpoint.Endpoint
+	CreateQuote    endpoint.Endpoint
+	ExecuteQuote   endpoint.Endpoint
 }
 
 func MakeConversionEndpoints(svc service.ConversionService) ConversionEndpoints {
 	return ConversionEndpoints{
-		Convert:       makeConvertEndpoint(svc),
-		GetRate:       makeGetRateEndpoint(svc),
-		Health:        makeHealthEndpoint(),
-		PrecisionInfo: makePrecisionInfoEndpoint(),
+		Convert:        makeConvertEndpoint(svc),
+		GetRate:        makeGetRateEndpoint(svc),
+		Health:         makeHealthEndpoint(),
+		PrecisionInfo:  makePrecisionInfoEndpoint(),
+		ListCurrencies: makeListCurrenciesEndpoint(svc),
+		GetCurrency:    makeGetCurrencyEndpoint(svc),
+		ConvertBatch:   makeConvertBatchEndpoint(svc),
+		ConvertMulti:   makeConvertMultiEndpoint(svc),
+		RateHistory:    makeRateHistoryEndpoint(svc),
+		RateStats:      makeRateStatsEndpoint(svc),
+		Candles:        makeCandlesEndpoint(svc),
+		PeriodAverage:  makePeriodAverageEndpoint(svc),
+		ClosingRate:    makeClosingRateEndpoint(svc),
+		CreateQuote:    makeCreateQuoteEndpoint(svc),
+		ExecuteQuote:   makeExecuteQuoteEndpoint(svc),
 	}
 }
 
@@ -32,16 +54,101 @@ func makeConvertEndpoint(svc service.ConversionService) endpoint.Endpoint {
 	}
 }
 
+func makeConvertBatchEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		reqs := request.([]domain.ConversionRequest)
+		return svc.ConvertBatch(ctx, reqs)
+	}
+}
+
+func makeConvertMultiEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.MultiConversionRequest)
+		return svc.ConvertMulti(ctx, &req)
+	}
+}
+
+func makeCreateQuoteEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.ConversionRequest)
+		return svc.CreateQuote(ctx, &req)
+	}
+}
+
+func makeExecuteQuoteEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(utils.ExecuteQuoteRequest)
+		return svc.ExecuteQuote(ctx, req.ID)
+	}
+}
+
 func makeGetRateEndpoint(svc service.ConversionService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(utils.GetRateRequest)
-		rate, err := svc.GetPrecisionRate(ctx, req.From, req.To)
+		priced, err := svc.GetPricedRate(ctx, req.From, req.To, req.Amount, req.Segment)
 		if err != nil {
 			return nil, err
 		}
 		return struct {
 			Rate domain.Money `json:"rate"`
-		}{Rate: rate}, nil
+			domain.PricedRate
+		}{Rate: priced.Bid, PricedRate: *priced}, nil
+	}
+}
+
+func makeRateHistoryEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.RateHistoryRequest)
+		return svc.GetRateHistory(ctx, &req)
+	}
+}
+
+func makeRateStatsEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.RateHistoryRequest)
+		return svc.GetRateStats(ctx, &req)
+	}
+}
+
+func makeCandlesEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.CandleRequest)
+		return svc.GetCandles(ctx, &req)
+	}
+}
+
+func makePeriodAverageEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.PeriodRateRequest)
+		return svc.GetPeriodAverage(ctx, &req)
+	}
+}
+
+func makeClosingRateEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(domain.PeriodRateRequest)
+		return svc.GetClosingRate(ctx, &req)
+	}
+}
+
+func makeListCurrenciesEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		filter := request.(domain.CurrencyFilter)
+		currencies, err := svc.ListCurrencies(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return struct {
+			Currencies []domain.CurrencyInfo `json:"currencies"`
+			Count      int                   `json:"count"`
+		}{currencies, len(currencies)}, nil
+	}
+}
+
+func makeGetCurrencyEndpoint(svc service.ConversionService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(utils.GetCurrencyRequest)
+		return svc.GetCurrency(ctx, req.Code)
 	}
 }
 
@@ -53,9 +160,15 @@ func makeHealthEndpoint() endpoint.Endpoint {
 
 func makePrecisionInfoEndpoint() endpoint.Endpoint {
 	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		currencies := domain.EnabledCurrencies()
+		scales := make(map[string]int, len(currencies))
+		for _, currency := range currencies {
+			scales[currency.Code] = currency.MinorUnits
+		}
 		return struct {
-			Scale   int  `json:"scale"`
-			Enabled bool `json:"enabled"`
-		}{domain.DefaultScale, true}, nil
+			RateScale  int            `json:"rate_scale"`
+			Currencies map[string]int `json:"currencies"`
+			Enabled    bool           `json:"enabled"`
+		}{domain.DefaultScale, scales, true}, nil
 	}
 }
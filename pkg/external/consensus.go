@@ -0,0 +1,239 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// ConsensusMethod is how the rates that survive outlier rejection are
+// combined.
+type ConsensusMethod string
+
+const (
+	ConsensusMedian      ConsensusMethod = "median"
+	ConsensusTrimmedMean ConsensusMethod = "trimmed_mean"
+)
+
+// ParseConsensusMethod parses a method name; the empty string means
+// ConsensusMedian.
+func ParseConsensusMethod(s string) (ConsensusMethod, error) {
+	switch method := ConsensusMethod(strings.ToLower(strings.TrimSpace(s))); method {
+	case "":
+		return ConsensusMedian, nil
+	case ConsensusMedian, ConsensusTrimmedMean:
+		return method, nil
+	default:
+		return "", fmt.Errorf("unknown consensus method %q (want median or trimmed_mean)", s)
+	}
+}
+
+// ConsensusPolicy configures a Consensus. Rates further than
+// MaxDeviationBps from the median of all answers are dropped as outliers
+// (0 keeps every answer). TrimmedMean then leaves out TrimPercent of the
+// remaining rates at each end before averaging. At least MinSources rates
+// must remain, or the lookup fails.
+type ConsensusPolicy struct {
+	Method          ConsensusMethod
+	TrimPercent     int
+	MaxDeviationBps int
+	MinSources      int
+}
+
+// Consensus is an ExchangeRateAPI that asks every member provider at once
+// and answers with a rate they agree on, so one provider publishing a bad
+// tick does not reach customers. Responses list the providers whose rates
+// were used in Sources.
+type Consensus struct {
+	logger  log.Logger
+	policy  ConsensusPolicy
+	members []Provider
+}
+
+// sourcedRate is one member's answer.
+type sourcedRate struct {
+	name string
+	rate domain.Money
+}
+
+// NewConsensus builds a consensus over members. A MinSources below one
+// means one.
+func NewConsensus(logger log.Logger, policy ConsensusPolicy, members ...Provider) (*Consensus, error) {
+	if len(members) == 0 {
+		return nil, errors.New("consensus needs at least one member provider")
+	}
+	if policy.Method == "" {
+		policy.Method = ConsensusMedian
+	}
+	if policy.Method != ConsensusMedian && policy.Method != ConsensusTrimmedMean {
+		return nil, fmt.Errorf("unknown consensus method %q", policy.Method)
+	}
+	if policy.TrimPercent < 0 || policy.TrimPercent >= 50 {
+		return nil, fmt.Errorf("trim percent must be between 0 and 49, got %d", policy.TrimPercent)
+	}
+	if policy.MaxDeviationBps < 0 {
+		return nil, fmt.Errorf("max deviation must not be negative, got %d bps", policy.MaxDeviationBps)
+	}
+	if policy.MinSources < 1 {
+		policy.MinSources = 1
+	}
+	if policy.MinSources > len(members) {
+		return nil, fmt.Errorf("min sources %d exceeds the %d member providers", policy.MinSources, len(members))
+	}
+	for i, m := range members {
+		if m.Name == "" || m.API == nil {
+			return nil, fmt.Errorf("consensus member %d needs a name and an implementation", i)
+		}
+	}
+	return &Consensus{logger: logger, policy: policy, members: members}, nil
+}
+
+func (c *Consensus) Convert(ctx context.Context, req domain.ExchangeRate) (domain.ExchangeRateResponse, error) {
+	rate, sources, err := c.consensus(ctx, func(ctx context.Context, api ExchangeRateAPI) (domain.Money, error) {
+		return api.GetRate(ctx, req.From, req.To, req.Date)
+	})
+	if err != nil {
+		return domain.ExchangeRateResponse{Success: false}, err
+	}
+	return domain.ExchangeRateResponse{
+		Success:   true,
+		Rate:      rate,
+		Amount:    req.Rate.Multiply(rate, domain.RoundHalfEven),
+		Timestamp: time.Now(),
+		Sources:   sources,
+	}, nil
+}
+
+func (c *Consensus) GetRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
+	rate, _, err := c.consensus(ctx, func(ctx context.Context, api ExchangeRateAPI) (domain.Money, error) {
+		return api.GetRate(ctx, from, to, date)
+	})
+	return rate, err
+}
+
+// consensus asks every member concurrently, rejects outliers and combines
+// the rest. It returns the rate and the names of the members used, in
+// member order.
+func (c *Consensus) consensus(ctx context.Context, fetch func(context.Context, ExchangeRateAPI) (domain.Money, error)) (domain.Money, []string, error) {
+	answers := make([]*sourcedRate, len(c.members))
+	errs := make([]error, len(c.members))
+	var wg sync.WaitGroup
+	for i, m := range c.members {
+		wg.Add(1)
+		go func(i int, m Provider) {
+			defer wg.Done()
+			memberCtx, cancel := m.context(ctx)
+			defer cancel()
+			rate, err := fetch(memberCtx, m.API)
+			if err == nil && !rate.IsPositive() {
+				err = errors.New("non-positive rate")
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", m.Name, err)
+				return
+			}
+			answers[i] = &sourcedRate{name: m.Name, rate: rate}
+		}(i, m)
+	}
+	wg.Wait()
+
+	var rates []sourcedRate
+	for i, answer := range answers {
+		if answer == nil {
+			level.Warn(c.logger).Log("msg", "consensus member failed", "provider", c.members[i].Name, "error", errs[i])
+			continue
+		}
+		rates = append(rates, *answer)
+	}
+	if len(rates) == 0 {
+		return domain.Money{}, nil, fmt.Errorf("no consensus member answered: %w", errors.Join(errs...))
+	}
+
+	kept := c.rejectOutliers(rates)
+	if len(kept) < c.policy.MinSources {
+		return domain.Money{}, nil, fmt.Errorf("consensus needs %d agreeing sources, got %d of %d answers: %w",
+			c.policy.MinSources, len(kept), len(rates), errors.Join(errs...))
+	}
+
+	sources := make([]string, len(kept))
+	for i, r := range kept {
+		sources[i] = r.name
+	}
+	return c.combine(kept), sources, nil
+}
+
+// rejectOutliers drops the rates further than MaxDeviationBps from the
+// median of all of them. The comparison is exact:
+// |rate-median|*10000 > median*bps.
+func (c *Consensus) rejectOutliers(rates []sourcedRate) []sourcedRate {
+	if c.policy.MaxDeviationBps == 0 {
+		return rates
+	}
+
+	median := medianOf(rates)
+	limit := median.MultiplyExact(domain.Money{Amount: big.NewInt(int64(c.policy.MaxDeviationBps))})
+	bpsPerUnit := domain.Money{Amount: big.NewInt(10000)}
+
+	kept := make([]sourcedRate, 0, len(rates))
+	for _, r := range rates {
+		deviation := r.rate.Subtract(median)
+		if deviation.IsNegative() {
+			deviation = median.Subtract(r.rate)
+		}
+		if deviation.MultiplyExact(bpsPerUnit).Cmp(limit) > 0 {
+			level.Warn(c.logger).Log("msg", "consensus rejected outlier", "provider", r.name,
+				"rate", r.rate.String(), "median", median.String(), "max_deviation_bps", c.policy.MaxDeviationBps)
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// combine reduces rates to one according to the policy method.
+func (c *Consensus) combine(rates []sourcedRate) domain.Money {
+	if c.policy.Method != ConsensusTrimmedMean {
+		return medianOf(rates)
+	}
+
+	sorted := sortedRates(rates)
+	trim := len(sorted) * c.policy.TrimPercent / 100
+	sorted = sorted[trim : len(sorted)-trim]
+
+	sum := domain.Money{Amount: new(big.Int)}
+	for _, rate := range sorted {
+		sum = sum.Add(rate)
+	}
+	count := domain.Money{Amount: big.NewInt(int64(len(sorted)))}
+	return sum.Quo(count, domain.DefaultScale, domain.RoundHalfEven)
+}
+
+// medianOf returns the middle rate, or the mean of the two middle ones,
+// rounded half-even to domain.DefaultScale.
+func medianOf(rates []sourcedRate) domain.Money {
+	sorted := sortedRates(rates)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid].ConvertToScale(domain.DefaultScale, domain.RoundHalfEven)
+	}
+	two := domain.Money{Amount: big.NewInt(2)}
+	return sorted[mid-1].Add(sorted[mid]).Quo(two, domain.DefaultScale, domain.RoundHalfEven)
+}
+
+func sortedRates(rates []sourcedRate) []domain.Money {
+	sorted := make([]domain.Money, len(rates))
+	for i, r := range rates {
+		sorted[i] = r.rate
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return sorted
+}
@@ -0,0 +1,281 @@
+package external
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// ECBRefreshInterval is how long a parsed ECB feed is used before it is
+// loaded again. The ECB publishes once a working day, around 16:00 CET.
+const ECBRefreshInterval = time.Hour
+
+// ecbRetryInterval is how long a feed that failed to load is left alone
+// before the next attempt, so an unreachable source is not read again on
+// every lookup.
+const ecbRetryInterval = time.Minute
+
+// ecbMaxLookbackDays bounds the search for the last published day before a
+// requested date: the ECB does not publish at weekends or on TARGET
+// holidays.
+const ecbMaxLookbackDays = 7
+
+// ECBProvider serves the European Central Bank euro foreign exchange
+// reference rates from the eurofxref XML feeds. Every rate is quoted
+// against EUR; other pairs are rebased through it.
+//
+// The latest feed (eurofxref-daily.xml) answers for the current day and
+// the history feed (eurofxref-hist-90d.xml or eurofxref-hist.xml) for
+// earlier ones, so a series of historical lookups parses the history once
+// instead of making a request per day. Either source may be an http(s) URL
+// or a local file path.
+type ECBProvider struct {
+	latest     string
+	history    string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	sources map[string]*ecbSource
+}
+
+// ecbSource is the state of one feed source: the last copy parsed, when it
+// was last read and how that went. refreshing is open while a read is in
+// flight and closed when it ends.
+type ecbSource struct {
+	feed       *ecbFeed
+	checked    time.Time
+	err        error
+	refreshing chan struct{}
+}
+
+// ecbFeed holds the rates of one feed by day, and its days in ascending
+// order.
+type ecbFeed struct {
+	days  []time.Time
+	rates map[time.Time]map[string]domain.Money
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// NewECBProvider reads the latest rates from latest and earlier ones from
+// history. history may be empty, in which case latest serves every date it
+// covers; a 90-day feed works as both.
+func NewECBProvider(latest, history string, timeout time.Duration) *ECBProvider {
+	return &ECBProvider{
+		latest:     latest,
+		history:    history,
+		httpClient: &http.Client{Timeout: timeout},
+		sources:    make(map[string]*ecbSource),
+	}
+}
+
+func (p *ECBProvider) Convert(ctx context.Context, req domain.ExchangeRate) (domain.ExchangeRateResponse, error) {
+	rate, day, err := p.rate(ctx, req.From, req.To, req.Date)
+	if err != nil {
+		return domain.ExchangeRateResponse{Success: false}, err
+	}
+	return domain.ExchangeRateResponse{
+		Success:   true,
+		Rate:      rate,
+		Amount:    req.Rate.Multiply(rate, domain.RoundHalfEven),
+		Timestamp: day,
+	}, nil
+}
+
+func (p *ECBProvider) GetRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
+	rate, _, err := p.rate(ctx, from, to, date)
+	return rate, err
+}
+
+// rate rebases the EUR reference rates of the last day published on or
+// before date (the latest day when date is zero) to from/to. It also
+// returns that day.
+func (p *ECBProvider) rate(ctx context.Context, from, to string, date time.Time) (domain.Money, time.Time, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	details := map[string]interface{}{"from": from, "to": to}
+
+	feed, err := p.feedFor(ctx, date)
+	if err != nil {
+		return domain.Money{}, time.Time{}, err
+	}
+	day, ok := feed.dayOnOrBefore(date)
+	if !ok {
+		details["date"] = date.Format("2006-01-02")
+		return domain.Money{}, time.Time{}, domain.NewError(domain.ErrRateNotFound, "no ECB reference rate published for the date", details)
+	}
+
+	rates := feed.rates[day]
+	fromRate, fromOK := rates[from]
+	toRate, toOK := rates[to]
+	if !fromOK || !toOK {
+		return domain.Money{}, time.Time{}, domain.NewError(domain.ErrRateNotFound, "currency not covered by the ECB reference rates", details)
+	}
+	return toRate.Quo(fromRate, domain.DefaultScale, domain.RoundHalfEven), day, nil
+}
+
+// feedFor picks the feed that covers date: the latest feed for today or a
+// zero date and whenever there is no history feed, the history otherwise.
+func (p *ECBProvider) feedFor(ctx context.Context, date time.Time) (*ecbFeed, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if p.history == "" || date.IsZero() || !date.Before(today) {
+		return p.load(ctx, p.latest)
+	}
+	return p.load(ctx, p.history)
+}
+
+// load returns the parsed feed at source, reading it again once it is
+// older than ECBRefreshInterval. A failed read is not tried again for
+// ecbRetryInterval and keeps serving the previous copy meanwhile:
+// yesterday's reference rates beat none. The read runs in the background,
+// outside the lock and detached from the caller's context, so one caller
+// giving up neither cancels it nor leaves its error to the others.
+// Lookups use the previous copy while it runs, or wait for it as long as
+// their own context allows when there is none.
+func (p *ECBProvider) load(ctx context.Context, source string) (*ecbFeed, error) {
+	for {
+		p.mu.Lock()
+		s, ok := p.sources[source]
+		if !ok {
+			s = &ecbSource{}
+			p.sources[source] = s
+		}
+		wait := ECBRefreshInterval
+		if s.err != nil {
+			wait = ecbRetryInterval
+		}
+		if !s.checked.IsZero() && time.Since(s.checked) < wait {
+			feed, err := s.feed, s.err
+			p.mu.Unlock()
+			if feed != nil {
+				return feed, nil
+			}
+			return nil, err
+		}
+		if s.refreshing == nil {
+			s.refreshing = make(chan struct{})
+			go p.refresh(context.WithoutCancel(ctx), source, s)
+		}
+		feed, refreshing := s.feed, s.refreshing
+		p.mu.Unlock()
+		if feed != nil {
+			return feed, nil
+		}
+		select {
+		case <-refreshing:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// refresh reads source into s and wakes the lookups waiting for it. The
+// read is bounded by the HTTP client's timeout.
+func (p *ECBProvider) refresh(ctx context.Context, source string, s *ecbSource) {
+	fresh, err := p.read(ctx, source)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	close(s.refreshing)
+	s.refreshing = nil
+	s.checked = time.Now()
+	s.err = nil
+	if err != nil {
+		s.err = fmt.Errorf("loading ECB feed %s: %w", source, err)
+	} else {
+		s.feed = fresh
+	}
+}
+
+func (p *ECBProvider) read(ctx context.Context, source string) (*ecbFeed, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return parseECBFeed(file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parseECBFeed(resp.Body)
+}
+
+// parseECBFeed parses a eurofxref document. EUR itself is added to every
+// day at 1 so it rebases like any other currency.
+func parseECBFeed(r io.Reader) (*ecbFeed, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid eurofxref XML: %w", err)
+	}
+	if len(envelope.Days) == 0 {
+		return nil, fmt.Errorf("eurofxref XML has no rates")
+	}
+
+	feed := &ecbFeed{rates: make(map[time.Time]map[string]domain.Money, len(envelope.Days))}
+	for _, d := range envelope.Days {
+		day, err := time.Parse("2006-01-02", d.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid eurofxref date %q: %w", d.Time, err)
+		}
+		rates := map[string]domain.Money{"EUR": domain.NewMoney(1, domain.DefaultScale)}
+		for _, r := range d.Rates {
+			rate, err := domain.ParseMoney(r.Rate, domain.MaxScale, domain.RoundUnnecessary)
+			if err != nil || !rate.IsPositive() {
+				return nil, fmt.Errorf("invalid eurofxref rate %q for %s on %s", r.Rate, r.Currency, d.Time)
+			}
+			rates[strings.ToUpper(r.Currency)] = rate
+		}
+		if _, dup := feed.rates[day]; !dup {
+			feed.days = append(feed.days, day)
+		}
+		feed.rates[day] = rates
+	}
+	sort.Slice(feed.days, func(i, j int) bool { return feed.days[i].Before(feed.days[j]) })
+	return feed, nil
+}
+
+// dayOnOrBefore finds the last published day on or before date, at most
+// ecbMaxLookbackDays earlier. A zero date means the latest day.
+func (f *ecbFeed) dayOnOrBefore(date time.Time) (time.Time, bool) {
+	if date.IsZero() {
+		return f.days[len(f.days)-1], true
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	i := sort.Search(len(f.days), func(i int) bool { return f.days[i].After(date) })
+	if i == 0 {
+		return time.Time{}, false
+	}
+	day := f.days[i-1]
+	if date.Sub(day) > ecbMaxLookbackDays*24*time.Hour {
+		return time.Time{}, false
+	}
+	return day, true
+}
@@ -3,12 +3,14 @@ package external
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log"
 )
 
 type ExchangeRateAPI interface {
@@ -20,16 +22,44 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	logger     log.Logger
+	retry      RetryPolicy
 }
 
-func NewClient(baseURL, apiKey string, timeout time.Duration) *Client {
-	return &Client{
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithRetryPolicy sets how failed requests are retried; the default is
+// DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithLogger logs every attempt to logger.
+func WithLogger(logger log.Logger) ClientOption {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+func NewClient(baseURL, apiKey string, timeout time.Duration, opts ...ClientOption) *Client {
+	c := &Client{
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
+		logger: log.NewNopLogger(),
+		retry:  DefaultRetryPolicy(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) Convert(ctx context.Context, params domain.ExchangeRate) (domain.ExchangeRateResponse, error) {
@@ -49,36 +79,29 @@ func (c *Client) Convert(ctx context.Context, params domain.ExchangeRate) (domai
 	}
 
 	fullURL := fmt.Sprintf("%s/convert?%s", c.baseURL, q.Encode())
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	var apiResp convertResponse
+	logger := log.With(c.logger, "base_url", c.baseURL, "from", params.From, "to", params.To)
+	err := retry(ctx, logger, c.retry, func(ctx context.Context) error {
+		apiResp = convertResponse{}
+		return c.getJSON(ctx, fullURL, &apiResp)
+	})
 	if err != nil {
 		return domain.ExchangeRateResponse{Success: false}, err
 	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return domain.ExchangeRateResponse{Success: false}, err
+	if !apiResp.Success {
+		return domain.ExchangeRateResponse{Success: false}, fmt.Errorf("conversion failed: %s", apiResp.Error.Info)
 	}
-	defer resp.Body.Close()
 
-	var apiResp struct {
-		Success bool    `json:"success"`
-		Result  float64 `json:"result"`
-		Info    struct {
-			Rate      float64 `json:"rate"`
-			Timestamp int64   `json:"timestamp"`
-		} `json:"info,omitempty"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+	resultMoney, err := domain.NewMoneyFromFloat(apiResp.Result, domain.DefaultScale)
+	if err != nil {
 		return domain.ExchangeRateResponse{Success: false}, err
 	}
-	if !apiResp.Success {
-		return domain.ExchangeRateResponse{Success: false}, fmt.Errorf("conversion failed")
-	}
-
-	resultMoney := domain.NewMoney(apiResp.Result, domain.DefaultScale)
 	var rateMoney domain.Money
 	if apiResp.Info.Rate > 0 {
-		rateMoney = domain.NewMoney(apiResp.Info.Rate, domain.DefaultScale)
+		rateMoney, err = domain.NewMoneyFromFloat(apiResp.Info.Rate, domain.DefaultScale)
+		if err != nil {
+			return domain.ExchangeRateResponse{Success: false}, err
+		}
 	} else {
 		rateMoney = resultMoney
 	}
@@ -96,6 +119,44 @@ func (c *Client) Convert(ctx context.Context, params domain.ExchangeRate) (domai
 	}, nil
 }
 
+type convertResponse struct {
+	Success bool    `json:"success"`
+	Result  float64 `json:"result"`
+	Info    struct {
+		Rate      float64 `json:"rate"`
+		Timestamp int64   `json:"timestamp"`
+	} `json:"info,omitempty"`
+	Error struct {
+		Code int    `json:"code"`
+		Info string `json:"info"`
+	} `json:"error,omitempty"`
+}
+
+// getJSON makes one GET request and decodes the response into v. A status
+// outside 2xx is returned as a *StatusError.
+func (c *Client) getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The query carries the API key; keep it out of errors and logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
 func (c *Client) GetRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
 	rateReq := domain.ExchangeRate{
 		From: from,
@@ -125,13 +186,17 @@ func (c *Client) BatchConvert(ctx context.Context, requests []domain.ExchangeRat
 	return results, nil
 }
 
+// GetHistoricalRates fetches the rate for every day from startDate to
+// endDate. A day that fails is returned as an unsuccessful entry and its
+// error is joined into the returned error, so gaps are never dropped.
 func (c *Client) GetHistoricalRates(ctx context.Context, from, to string, startDate, endDate time.Time) ([]domain.ExchangeRateResponse, error) {
 	var results []domain.ExchangeRateResponse
-	current := startDate
-	for !current.After(endDate) {
+	var errs []error
+	for current := startDate; !current.After(endDate); current = current.AddDate(0, 0, 1) {
 		rate, err := c.GetRate(ctx, from, to, current)
 		if err != nil {
-			current = current.AddDate(0, 0, 1)
+			results = append(results, domain.ExchangeRateResponse{Success: false, Timestamp: current})
+			errs = append(errs, fmt.Errorf("%s: %w", current.Format("2006-01-02"), err))
 			continue
 		}
 
@@ -141,9 +206,8 @@ func (c *Client) GetHistoricalRates(ctx context.Context, from, to string, startD
 			Amount:    rate,
 			Timestamp: current,
 		})
-		current = current.AddDate(0, 0, 1)
 	}
-	return results, nil
+	return results, errors.Join(errs...)
 }
 
 func (c *Client) ValidateConnection(ctx context.Context) error {
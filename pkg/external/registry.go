@@ -0,0 +1,203 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// DefaultCooldown is how long a failed provider is skipped before it is
+// tried again.
+const DefaultCooldown = 30 * time.Second
+
+// Provider is a named rate source. Timeout, when set, bounds every call so
+// a hanging provider fails over instead of stalling the request.
+type Provider struct {
+	Name    string
+	API     ExchangeRateAPI
+	Timeout time.Duration
+}
+
+func (p Provider) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.Timeout > 0 {
+		return context.WithTimeout(ctx, p.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// ProviderHealth is what the registry knows about one provider. A provider
+// is unhealthy after a failed call until a call succeeds again; it is not
+// tried before RetryAt unless every provider is down.
+type ProviderHealth struct {
+	Name                string    `json:"name"`
+	Priority            int       `json:"priority"`
+	Healthy             bool      `json:"healthy"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastSuccess         time.Time `json:"last_success"`
+	LastFailure         time.Time `json:"last_failure"`
+	RetryAt             time.Time `json:"retry_at"`
+}
+
+// Registry is an ExchangeRateAPI that asks its providers in priority order
+// and fails over to the next one when a provider errors, times out or does
+// not quote the requested rate. A provider that errored or timed out sits
+// out for the cooldown, after which it is tried first again, so a recovered
+// primary takes back the traffic.
+type Registry struct {
+	logger    log.Logger
+	providers []Provider
+	cooldown  time.Duration
+
+	mu     sync.Mutex
+	health []ProviderHealth
+}
+
+// NewRegistry builds a registry over providers, highest priority first. A
+// non-positive cooldown means DefaultCooldown.
+func NewRegistry(logger log.Logger, cooldown time.Duration, providers ...Provider) (*Registry, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("at least one rate provider is required")
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+
+	r := &Registry{
+		logger:    logger,
+		providers: providers,
+		cooldown:  cooldown,
+		health:    make([]ProviderHealth, len(providers)),
+	}
+	seen := make(map[string]bool, len(providers))
+	for i, p := range providers {
+		if p.Name == "" || p.API == nil {
+			return nil, fmt.Errorf("rate provider %d needs a name and an implementation", i)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("duplicate rate provider %q", p.Name)
+		}
+		seen[p.Name] = true
+		r.health[i] = ProviderHealth{Name: p.Name, Priority: i + 1, Healthy: true}
+	}
+	return r, nil
+}
+
+// Health returns a snapshot of every provider's health in priority order.
+func (r *Registry) Health() []ProviderHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ProviderHealth(nil), r.health...)
+}
+
+func (r *Registry) Convert(ctx context.Context, req domain.ExchangeRate) (domain.ExchangeRateResponse, error) {
+	var resp domain.ExchangeRateResponse
+	err := r.do(ctx, func(ctx context.Context, api ExchangeRateAPI) error {
+		var err error
+		resp, err = api.Convert(ctx, req)
+		if err == nil && !resp.Success {
+			err = errors.New("conversion failed")
+		}
+		return err
+	})
+	if err != nil {
+		return domain.ExchangeRateResponse{Success: false}, err
+	}
+	return resp, nil
+}
+
+func (r *Registry) GetRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
+	var rate domain.Money
+	err := r.do(ctx, func(ctx context.Context, api ExchangeRateAPI) error {
+		var err error
+		rate, err = api.GetRate(ctx, from, to, date)
+		return err
+	})
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return rate, nil
+}
+
+// do runs call against each available provider in turn until one succeeds.
+// When every provider is cooling down they are all tried anyway: a stale
+// verdict should not fail a request that a provider could now serve.
+func (r *Registry) do(ctx context.Context, call func(context.Context, ExchangeRateAPI) error) error {
+	order := r.available(time.Now())
+	if len(order) == 0 {
+		order = make([]int, len(r.providers))
+		for i := range order {
+			order[i] = i
+		}
+	}
+
+	var errs []error
+	for _, i := range order {
+		p := r.providers[i]
+		attemptCtx, cancel := p.context(ctx)
+		err := call(attemptCtx, p.API)
+		cancel()
+
+		if err == nil {
+			r.markSuccess(i)
+			return nil
+		}
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the provider.
+			return ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+		if errors.Is(err, domain.ErrRateNotFound) {
+			// The provider answered; it just does not quote this pair or date.
+			r.markSuccess(i)
+			continue
+		}
+		r.markFailure(i, err)
+		level.Warn(r.logger).Log("msg", "rate provider failed, trying next", "provider", p.Name, "error", err)
+	}
+	return fmt.Errorf("all rate providers failed: %w", errors.Join(errs...))
+}
+
+func (r *Registry) available(now time.Time) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order := make([]int, 0, len(r.health))
+	for i, h := range r.health {
+		if h.Healthy || !now.Before(h.RetryAt) {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+func (r *Registry) markSuccess(i int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := &r.health[i]
+	if !h.Healthy {
+		level.Info(r.logger).Log("msg", "rate provider recovered", "provider", h.Name)
+	}
+	h.Healthy = true
+	h.ConsecutiveFailures = 0
+	h.LastError = ""
+	h.LastSuccess = time.Now()
+	h.RetryAt = time.Time{}
+}
+
+func (r *Registry) markFailure(i int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	h := &r.health[i]
+	h.Healthy = false
+	h.ConsecutiveFailures++
+	h.LastError = err.Error()
+	h.LastFailure = now
+	h.RetryAt = now.Add(r.cooldown)
+}
@@ -0,0 +1,182 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// ErrorClass groups the failures a RetryPolicy may retry.
+type ErrorClass string
+
+const (
+	// ClassTimeout is a request that ran out of time, including the
+	// client's own per-attempt timeout.
+	ClassTimeout ErrorClass = "timeout"
+	// ClassNetwork is a DNS failure, a refused or reset connection, or a
+	// response cut short.
+	ClassNetwork ErrorClass = "network"
+	// ClassStatus is an HTTP response whose status is in
+	// RetryPolicy.RetryableStatus.
+	ClassStatus ErrorClass = "status"
+)
+
+// ParseErrorClass parses an error class name.
+func ParseErrorClass(s string) (ErrorClass, error) {
+	switch class := ErrorClass(strings.ToLower(strings.TrimSpace(s))); class {
+	case ClassTimeout, ClassNetwork, ClassStatus:
+		return class, nil
+	default:
+		return "", fmt.Errorf("unknown error class %q (want timeout, network or status)", s)
+	}
+}
+
+// RetryPolicy says how often and how patiently a failed request is tried
+// again. The delay before retry n is BaseDelay*2^(n-1), capped at MaxDelay
+// and then moved at random by up to Jitter (a fraction, 0.2 meaning ±20%)
+// so that clients failing together do not retry together. Only errors in
+// RetryOn are retried; MaxAttempts counts the first try, so 1 disables
+// retries.
+type RetryPolicy struct {
+	MaxAttempts     int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	Jitter          float64
+	RetryableStatus []int
+	RetryOn         []ErrorClass
+}
+
+// DefaultRetryPolicy retries timeouts, network errors and the statuses
+// that signal a transient upstream problem, three attempts in all.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     3,
+		BaseDelay:       200 * time.Millisecond,
+		MaxDelay:        2 * time.Second,
+		Jitter:          0.2,
+		RetryableStatus: []int{429, 502, 503, 504},
+		RetryOn:         []ErrorClass{ClassTimeout, ClassNetwork, ClassStatus},
+	}
+}
+
+// Validate checks the policy is usable.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1, got %d", p.MaxAttempts)
+	}
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return errors.New("retry delays must not be negative")
+	}
+	if p.MaxDelay > 0 && p.BaseDelay > p.MaxDelay {
+		return fmt.Errorf("base delay %s exceeds max delay %s", p.BaseDelay, p.MaxDelay)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1, got %g", p.Jitter)
+	}
+	return nil
+}
+
+// delay returns the wait before the given retry, counting from 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+// retryable reports whether err is worth another attempt, and its class.
+func (p RetryPolicy) retryable(err error) (ErrorClass, bool) {
+	class, ok := classify(err)
+	if !ok || !slices.Contains(p.RetryOn, class) {
+		return class, false
+	}
+	var status *StatusError
+	if class == ClassStatus && errors.As(err, &status) {
+		return class, slices.Contains(p.RetryableStatus, status.Code)
+	}
+	return class, true
+}
+
+// StatusError is an HTTP response with an unexpected status.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return "unexpected status " + e.Status
+}
+
+func classify(err error) (ErrorClass, bool) {
+	var status *StatusError
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &status):
+		return ClassStatus, true
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ClassTimeout, true
+	case errors.As(err, &dnsErr), errors.As(err, &opErr),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return ClassNetwork, true
+	default:
+		return "", false
+	}
+}
+
+// retry runs attempt until it succeeds, fails with an error the policy
+// does not retry, or runs out of attempts. It never sleeps past the
+// context deadline: when the next delay would end after it, the last error
+// is returned at once.
+func retry(ctx context.Context, logger log.Logger, policy RetryPolicy, attempt func(context.Context) error) error {
+	for n := 1; ; n++ {
+		level.Debug(logger).Log("msg", "calling rate provider", "attempt", n, "max_attempts", policy.MaxAttempts)
+		err := attempt(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		class, ok := policy.retryable(err)
+		if !ok || n >= policy.MaxAttempts {
+			level.Warn(logger).Log("msg", "rate provider request failed", "attempt", n, "class", class, "error", err)
+			return err
+		}
+
+		delay := policy.delay(n)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			level.Warn(logger).Log("msg", "rate provider request failed, no time left to retry", "attempt", n, "class", class, "error", err)
+			return err
+		}
+		level.Warn(logger).Log("msg", "rate provider request failed, retrying", "attempt", n, "class", class, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
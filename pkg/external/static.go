@@ -0,0 +1,270 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+)
+
+// DefaultStaticPollInterval is how often a static rates file is checked
+// for changes.
+const DefaultStaticPollInterval = 5 * time.Second
+
+// StaticProvider serves rates from a local file of (date, from, to, rate)
+// rows, for air-gapped environments and deterministic tests. A ".json"
+// file holds an array of {"date", "from", "to", "rate"} objects; anything
+// else is read as CSV with a date,from,to,rate header. Rates are decimals,
+// rounded half-even to domain.DefaultScale, and a pair's inverse is
+// derived when only one direction is listed.
+//
+// The file is checked for changes at most once per poll interval, on use,
+// and reloaded when its modification time or size changed. A file that
+// fails to parse, typically caught halfway through a write, leaves the
+// previous rates in place.
+type StaticProvider struct {
+	path         string
+	fallbackDays int
+	pollInterval time.Duration
+
+	mu        sync.Mutex
+	rates     map[string][]staticRate
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+// staticRate is one row of the file; a pair's rows are kept in ascending
+// date order.
+type staticRate struct {
+	date time.Time
+	rate domain.Money
+}
+
+type staticRow struct {
+	Date string      `json:"date"`
+	From string      `json:"from"`
+	To   string      `json:"to"`
+	Rate json.Number `json:"rate"`
+}
+
+// NewStaticProvider loads the rates file at path. A dated lookup without a
+// row for that exact date uses the nearest previous date at most
+// fallbackDays earlier; 0 disables the fallback. A non-positive
+// pollInterval means DefaultStaticPollInterval.
+func NewStaticProvider(path string, fallbackDays int, pollInterval time.Duration) (*StaticProvider, error) {
+	if fallbackDays < 0 {
+		return nil, fmt.Errorf("fallback days must not be negative, got %d", fallbackDays)
+	}
+	if pollInterval <= 0 {
+		pollInterval = DefaultStaticPollInterval
+	}
+
+	p := &StaticProvider{path: path, fallbackDays: fallbackDays, pollInterval: pollInterval}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *StaticProvider) Convert(ctx context.Context, req domain.ExchangeRate) (domain.ExchangeRateResponse, error) {
+	rate, day, err := p.rate(req.From, req.To, req.Date)
+	if err != nil {
+		return domain.ExchangeRateResponse{Success: false}, err
+	}
+	return domain.ExchangeRateResponse{
+		Success:   true,
+		Rate:      rate,
+		Amount:    req.Rate.Multiply(rate, domain.RoundHalfEven),
+		Timestamp: day,
+	}, nil
+}
+
+func (p *StaticProvider) GetRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
+	rate, _, err := p.rate(from, to, date)
+	return rate, err
+}
+
+// rate looks up from/to on date (the latest row when date is zero),
+// falling back to the inverse of to/from. It also returns the date of the
+// row used.
+func (p *StaticProvider) rate(from, to string, date time.Time) (domain.Money, time.Time, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	p.refresh()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if row, ok := p.find(from+":"+to, date); ok {
+		return row.rate, row.date, nil
+	}
+	if row, ok := p.find(to+":"+from, date); ok {
+		one := domain.NewMoney(1, domain.DefaultScale)
+		return one.Quo(row.rate, domain.DefaultScale, domain.RoundHalfEven), row.date, nil
+	}
+
+	details := map[string]interface{}{"from": from, "to": to}
+	if !date.IsZero() {
+		details["date"] = date.Format("2006-01-02")
+	}
+	return domain.Money{}, time.Time{}, domain.NewError(domain.ErrRateNotFound, "no static rate for the pair and date", details)
+}
+
+// find returns the row of pair for date, or the nearest earlier one within
+// the fallback window. Callers hold p.mu.
+func (p *StaticProvider) find(pair string, date time.Time) (staticRate, bool) {
+	rows := p.rates[pair]
+	if len(rows) == 0 {
+		return staticRate{}, false
+	}
+	if date.IsZero() {
+		return rows[len(rows)-1], true
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	i := sort.Search(len(rows), func(i int) bool { return rows[i].date.After(date) })
+	if i == 0 {
+		return staticRate{}, false
+	}
+	row := rows[i-1]
+	if date.Sub(row.date) > time.Duration(p.fallbackDays)*24*time.Hour {
+		return staticRate{}, false
+	}
+	return row, true
+}
+
+// refresh reloads the file when it changed since the last load, checking
+// at most once per poll interval.
+func (p *StaticProvider) refresh() {
+	p.mu.Lock()
+	if time.Since(p.lastCheck) < p.pollInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.lastCheck = time.Now()
+	modTime, size := p.modTime, p.size
+	p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+		return
+	}
+	_ = p.reload()
+}
+
+func (p *StaticProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+
+	var rows []staticRow
+	if strings.EqualFold(filepath.Ext(p.path), ".json") {
+		err = json.Unmarshal(data, &rows)
+	} else {
+		rows, err = parseStaticCSV(bytes.NewReader(data))
+	}
+	if err != nil {
+		return fmt.Errorf("parsing static rates %s: %w", p.path, err)
+	}
+
+	rates, err := indexStaticRows(rows)
+	if err != nil {
+		return fmt.Errorf("parsing static rates %s: %w", p.path, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates = rates
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	p.lastCheck = time.Now()
+	return nil
+}
+
+func parseStaticCSV(r io.Reader) ([]staticRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "from", "to", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+
+	var rows []staticRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, staticRow{
+			Date: record[columns["date"]],
+			From: record[columns["from"]],
+			To:   record[columns["to"]],
+			Rate: json.Number(record[columns["rate"]]),
+		})
+	}
+}
+
+func indexStaticRows(rows []staticRow) (map[string][]staticRate, error) {
+	rates := make(map[string][]staticRate)
+	for i, row := range rows {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(row.Date))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid date %q", i+1, row.Date)
+		}
+		from, to := strings.ToUpper(strings.TrimSpace(row.From)), strings.ToUpper(strings.TrimSpace(row.To))
+		if len(from) != 3 || len(to) != 3 || from == to {
+			return nil, fmt.Errorf("row %d: invalid pair %q/%q", i+1, row.From, row.To)
+		}
+		rate, err := domain.ParseMoney(strings.TrimSpace(row.Rate.String()), domain.DefaultScale, domain.RoundHalfEven)
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("row %d: invalid rate %q", i+1, row.Rate)
+		}
+
+		pair := from + ":" + to
+		rates[pair] = append(rates[pair], staticRate{date: date, rate: rate})
+	}
+
+	for pair, list := range rates {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].date.Before(list[j].date) })
+		// A date listed twice keeps its last row.
+		deduped := list[:0]
+		for _, row := range list {
+			if n := len(deduped); n > 0 && deduped[n-1].date.Equal(row.date) {
+				deduped[n-1] = row
+				continue
+			}
+			deduped = append(deduped, row)
+		}
+		rates[pair] = deduped
+	}
+	return rates, nil
+}
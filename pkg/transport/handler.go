@@ -5,8 +5,10 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	kittransport "github.com/go-kit/kit/transport"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 
 	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/endpoint"
 	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/utils"
@@ -17,6 +19,7 @@ func MakeHTTPHandler(e endpoint.ConversionEndpoints, logger log.Logger) nethttp.
 	r.Use(middleware.RequestID, middleware.RealIP, middleware.Logger, middleware.Recoverer)
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(utils.EncodeError),
+		kithttp.ServerErrorHandler(kittransport.NewLogErrorHandler(level.Error(logger))),
 	}
 
 	r.Route("/api/v2", func(r chi.Router) {
@@ -50,6 +53,76 @@ func MakeHTTPHandler(e endpoint.ConversionEndpoints, logger log.Logger) nethttp.
 				opts...,
 			),
 		)
+		r.Method(
+			"GET",
+			"/rates/{from}/{to}/history",
+			kithttp.NewServer(
+				e.RateHistory,
+				utils.DecodeRateHistoryRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"GET",
+			"/rates/{from}/{to}/stats",
+			kithttp.NewServer(
+				e.RateStats,
+				utils.DecodeRateHistoryRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"GET",
+			"/rates/{from}/{to}/candles",
+			kithttp.NewServer(
+				e.Candles,
+				utils.DecodeCandleRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"GET",
+			"/rates/{from}/{to}/average",
+			kithttp.NewServer(
+				e.PeriodAverage,
+				utils.DecodePeriodAverageRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"GET",
+			"/rates/{from}/{to}/closing",
+			kithttp.NewServer(
+				e.ClosingRate,
+				utils.DecodeClosingRateRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"GET",
+			"/currencies",
+			kithttp.NewServer(
+				e.ListCurrencies,
+				utils.DecodeListCurrenciesRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"GET",
+			"/currencies/{code}",
+			kithttp.NewServer(
+				e.GetCurrency,
+				utils.DecodeGetCurrencyRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
 		r.Method(
 			"POST",
 			"/convert",
@@ -60,6 +133,46 @@ func MakeHTTPHandler(e endpoint.ConversionEndpoints, logger log.Logger) nethttp.
 				opts...,
 			),
 		)
+		r.Method(
+			"POST",
+			"/convert/batch",
+			kithttp.NewServer(
+				e.ConvertBatch,
+				utils.DecodeBatchConvertRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"POST",
+			"/convert/multi",
+			kithttp.NewServer(
+				e.ConvertMulti,
+				utils.DecodeMultiConvertRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"POST",
+			"/quotes",
+			kithttp.NewServer(
+				e.CreateQuote,
+				utils.DecodeConvertRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
+		r.Method(
+			"POST",
+			"/quotes/{id}/execute",
+			kithttp.NewServer(
+				e.ExecuteQuote,
+				utils.DecodeExecuteQuoteRequest,
+				utils.EncodeResponse,
+				opts...,
+			),
+		)
 	})
 
 	return r
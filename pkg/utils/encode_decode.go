@@ -3,65 +3,396 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 type errorer interface{ Error() error }
 
-type httpError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+// statusByCode maps each domain error code to the HTTP status it is
+// reported with. Codes missing here are served as 500.
+var statusByCode = map[string]int{
+	domain.ErrCodeInvalidRequest:      http.StatusBadRequest,
+	domain.ErrCodeInvalidAmount:       http.StatusBadRequest,
+	domain.ErrCodeInvalidDate:         http.StatusBadRequest,
+	domain.ErrCodeDateOutOfRange:      http.StatusBadRequest,
+	domain.ErrCodeUnsupportedCurrency: http.StatusBadRequest,
+	domain.ErrCodeSameCurrency:        http.StatusBadRequest,
+	domain.ErrCodeCurrencyNotFound:    http.StatusNotFound,
+	domain.ErrCodeRateNotFound:        http.StatusNotFound,
+	domain.ErrCodeAmountOverflow:      http.StatusUnprocessableEntity,
+	domain.ErrCodeBatchTooLarge:       http.StatusRequestEntityTooLarge,
+	domain.ErrCodeQuoteNotFound:       http.StatusNotFound,
+	domain.ErrCodeQuoteExpired:        http.StatusGone,
+	domain.ErrCodeSlippageExceeded:    http.StatusConflict,
+	domain.ErrCodeUpstreamUnavailable: http.StatusBadGateway,
+	domain.ErrCodeInternal:            http.StatusInternalServerError,
 }
 
-func (e *httpError) Error() string { return e.Message }
+func badRequest(message string) error {
+	return domain.NewError(domain.ErrInvalidRequest, message, nil)
+}
 
-func DecodeConvertRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req struct {
-		From   string       `json:"from"`
-		To     string       `json:"to"`
-		Amount domain.Money `json:"amount"`
-		Date   string       `json:"date,omitempty"`
+// decodeJSONBody decodes the request body into v, reporting malformed
+// JSON and out-of-range amounts as domain errors.
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return nil
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, &httpError{Code: http.StatusBadRequest, Message: "invalid JSON body"}
+	if errors.Is(err, domain.ErrOverflow) {
+		return domain.WrapError(domain.ErrAmountOverflow, err, nil)
 	}
-	if req.From == "" || req.To == "" || req.Amount.IsZero() {
-		return nil, &httpError{Code: http.StatusBadRequest, Message: "from, to and amount are required"}
+	return domain.NewError(domain.ErrInvalidRequest, "invalid JSON body", map[string]interface{}{"reason": err.Error()})
+}
+
+// convertRequestBody is the wire form of one conversion request.
+type convertRequestBody struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	Amount         domain.Money  `json:"amount"`
+	Date           string        `json:"date,omitempty"`
+	Rounding       string        `json:"rounding,omitempty"`
+	ExpectedRate   *domain.Money `json:"expected_rate,omitempty"`
+	MaxSlippageBps int           `json:"max_slippage_bps,omitempty"`
+	FixedSide      string        `json:"fixed_side,omitempty"`
+	Segment        string        `json:"segment,omitempty"`
+}
+
+func (b convertRequestBody) toConversionRequest() (domain.ConversionRequest, error) {
+	if b.From == "" || b.To == "" || b.Amount.IsZero() {
+		return domain.ConversionRequest{}, badRequest("from, to and amount are required")
 	}
 
 	var parsedDate time.Time
-	if req.Date != "" {
+	if b.Date != "" {
 		var err error
-		parsedDate, err = time.Parse("2006-01-02", req.Date)
+		parsedDate, err = time.Parse("2006-01-02", b.Date)
 		if err != nil {
-			return nil, &httpError{Code: http.StatusBadRequest, Message: "invalid date format"}
+			return domain.ConversionRequest{}, domain.NewError(domain.ErrInvalidDate, "invalid date format, expected YYYY-MM-DD", map[string]interface{}{"field": "date"})
 		}
 	}
 
+	rounding, err := parseRounding(b.Rounding)
+	if err != nil {
+		return domain.ConversionRequest{}, err
+	}
+
+	fixedSide, err := domain.ParseFixedSide(b.FixedSide)
+	if err != nil {
+		return domain.ConversionRequest{}, domain.NewError(domain.ErrInvalidRequest, err.Error(), map[string]interface{}{"field": "fixed_side"})
+	}
+
 	return domain.ConversionRequest{
-		From:   req.From,
-		To:     req.To,
-		Amount: req.Amount,
-		Date:   parsedDate,
+		From:           b.From,
+		To:             b.To,
+		Amount:         b.Amount,
+		Date:           parsedDate,
+		Rounding:       rounding,
+		ExpectedRate:   b.ExpectedRate,
+		MaxSlippageBps: b.MaxSlippageBps,
+		FixedSide:      fixedSide,
+		Segment:        b.Segment,
 	}, nil
 }
 
+func DecodeConvertRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req convertRequestBody
+	if err := decodeJSONBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	convReq, err := req.toConversionRequest()
+	if err != nil {
+		return nil, err
+	}
+	if err := convReq.Validate(); err != nil {
+		return nil, err
+	}
+	return convReq, nil
+}
+
+type ExecuteQuoteRequest struct {
+	ID string `json:"id"`
+}
+
+func DecodeExecuteQuoteRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := ExecuteQuoteRequest{ID: chi.URLParam(r, "id")}
+	if req.ID == "" {
+		return nil, badRequest("quote id required")
+	}
+	return req, nil
+}
+
+// DecodeBatchConvertRequest decodes a JSON array of conversion requests.
+// Malformed items reject the whole batch; validation is left to the
+// service so that valid items still convert.
+func DecodeBatchConvertRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var items []convertRequestBody
+	if err := decodeJSONBody(r, &items); err != nil {
+		return nil, err
+	}
+
+	reqs := make([]domain.ConversionRequest, len(items))
+	for i, item := range items {
+		convReq, err := item.toConversionRequest()
+		if err != nil {
+			var domainErr *domain.Error
+			if errors.As(err, &domainErr) {
+				details := map[string]interface{}{"index": i}
+				for k, v := range domainErr.Details {
+					details[k] = v
+				}
+				return nil, domain.NewError(domainErr, fmt.Sprintf("item %d: %s", i, domainErr.Message), details)
+			}
+			return nil, err
+		}
+		reqs[i] = convReq
+	}
+	return reqs, nil
+}
+
+func DecodeMultiConvertRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req struct {
+		From     string       `json:"from"`
+		To       []string     `json:"to"`
+		Amount   domain.Money `json:"amount"`
+		Rounding string       `json:"rounding,omitempty"`
+		Segment  string       `json:"segment,omitempty"`
+	}
+	if err := decodeJSONBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.From == "" || len(req.To) == 0 || req.Amount.IsZero() {
+		return nil, badRequest("from, to and amount are required")
+	}
+
+	rounding, err := parseRounding(req.Rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	multiReq := domain.MultiConversionRequest{
+		From:     req.From,
+		To:       req.To,
+		Amount:   req.Amount,
+		Rounding: rounding,
+		Segment:  req.Segment,
+	}
+	if err := multiReq.Validate(); err != nil {
+		return nil, err
+	}
+	return multiReq, nil
+}
+
+// GetRateRequest asks for a pair's rates. Amount, in From, picks the
+// pricing tier; Segment the customer segment.
 type GetRateRequest struct {
-	From string `json:"from"`
-	To   string `json:"to"`
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Amount  domain.Money `json:"amount"`
+	Segment string       `json:"segment"`
 }
 
 func DecodeGetRateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
 	req := GetRateRequest{
+		From:    chi.URLParam(r, "from"),
+		To:      chi.URLParam(r, "to"),
+		Segment: q.Get("segment"),
+	}
+	from, to, err := domain.NormalizeCurrencyPair(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	req.From, req.To = from, to
+
+	if value := q.Get("amount"); value != "" {
+		amount, err := domain.ParseMoney(value, domain.MaxScale, domain.RoundUnnecessary)
+		if err != nil || amount.IsNegative() {
+			return nil, domain.NewError(domain.ErrInvalidAmount, "invalid amount", map[string]interface{}{"field": "amount"})
+		}
+		req.Amount = amount
+	}
+	return req, nil
+}
+
+// DecodeRateHistoryRequest reads the pair from the path and start, end
+// and interval from the query.
+func DecodeRateHistoryRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	req := domain.RateHistoryRequest{
 		From: chi.URLParam(r, "from"),
 		To:   chi.URLParam(r, "to"),
 	}
-	if req.From == "" || req.To == "" {
-		return nil, &httpError{Code: http.StatusBadRequest, Message: "from and to required"}
+
+	var err error
+	if req.Start, err = parseQueryDate(r, "start"); err != nil {
+		return nil, err
+	}
+	if req.End, err = parseQueryDate(r, "end"); err != nil {
+		return nil, err
+	}
+
+	interval, err := domain.ParseHistoryInterval(q.Get("interval"))
+	if err != nil {
+		return nil, domain.NewError(domain.ErrInvalidRequest, err.Error(), map[string]interface{}{"field": "interval"})
+	}
+	req.Interval = interval
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// decodePeriodRateRequest reads the options shared by average and closing
+// rates: scale, rounding and weekends.
+func decodePeriodRateRequest(r *http.Request) (domain.PeriodRateRequest, error) {
+	q := r.URL.Query()
+	req := domain.PeriodRateRequest{
+		From:  chi.URLParam(r, "from"),
+		To:    chi.URLParam(r, "to"),
+		Scale: domain.DefaultScale,
+	}
+
+	if value := q.Get("scale"); value != "" {
+		scale, err := strconv.Atoi(value)
+		if err != nil {
+			return req, domain.NewError(domain.ErrInvalidRequest, "scale must be an integer", map[string]interface{}{"field": "scale"})
+		}
+		req.Scale = scale
+	}
+
+	rounding, err := parseRounding(q.Get("rounding"))
+	if err != nil {
+		return req, err
+	}
+	req.Rounding = rounding
+
+	weekends, err := domain.ParseWeekendPolicy(q.Get("weekends"))
+	if err != nil {
+		return req, domain.NewError(domain.ErrInvalidRequest, err.Error(), map[string]interface{}{"field": "weekends"})
+	}
+	req.Weekends = weekends
+	return req, nil
+}
+
+// parseRounding parses a request's rounding mode. "unnecessary" is refused:
+// conversions and averages rarely come out exact, and the arithmetic
+// panics with domain.ErrInexact on an inexact result.
+func parseRounding(name string) (domain.RoundingMode, error) {
+	rounding, err := domain.ParseRoundingMode(name)
+	if err != nil {
+		return rounding, domain.NewError(domain.ErrInvalidRequest, err.Error(), map[string]interface{}{"field": "rounding"})
+	}
+	if rounding == domain.RoundUnnecessary {
+		return rounding, domain.NewError(domain.ErrInvalidRequest, "rounding mode unnecessary is not supported for conversions", map[string]interface{}{"field": "rounding"})
+	}
+	return rounding, nil
+}
+
+func parseQueryDate(r *http.Request, field string) (time.Time, error) {
+	value := r.URL.Query().Get(field)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, domain.NewError(domain.ErrInvalidDate, "invalid "+field+" date format, expected YYYY-MM-DD", map[string]interface{}{"field": field})
+	}
+	return date, nil
+}
+
+// DecodePeriodAverageRequest takes the period either as a calendar month or
+// quarter in "period" (2025-03, 2025-Q1) or as explicit "start" and "end".
+func DecodePeriodAverageRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodePeriodRateRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if period := r.URL.Query().Get("period"); period != "" {
+		req.Start, req.End, err = domain.ParsePeriod(period)
+		if err != nil {
+			return nil, domain.NewError(domain.ErrInvalidDate, err.Error(), map[string]interface{}{"field": "period"})
+		}
+	} else {
+		if req.Start, err = parseQueryDate(r, "start"); err != nil {
+			return nil, err
+		}
+		if req.End, err = parseQueryDate(r, "end"); err != nil {
+			return nil, err
+		}
+		if req.Start.IsZero() {
+			return nil, domain.NewError(domain.ErrInvalidDate, "period or start and end are required", map[string]interface{}{"field": "period"})
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// DecodeClosingRateRequest takes the period end either as "date" or as the
+// last day of a month or quarter in "period".
+func DecodeClosingRateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodePeriodRateRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if period := r.URL.Query().Get("period"); period != "" {
+		_, req.End, err = domain.ParsePeriod(period)
+		if err != nil {
+			return nil, domain.NewError(domain.ErrInvalidDate, err.Error(), map[string]interface{}{"field": "period"})
+		}
+	} else if req.End, err = parseQueryDate(r, "date"); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func DecodeCandleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	resolution, err := domain.ParseCandleResolution(r.URL.Query().Get("resolution"))
+	if err != nil {
+		return nil, domain.NewError(domain.ErrInvalidRequest, err.Error(), map[string]interface{}{"field": "resolution"})
+	}
+	return domain.CandleRequest{
+		From:       chi.URLParam(r, "from"),
+		To:         chi.URLParam(r, "to"),
+		Resolution: resolution,
+	}, nil
+}
+
+func DecodeListCurrenciesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	return domain.CurrencyFilter{
+		Prefix:  q.Get("prefix"),
+		Country: q.Get("country"),
+	}, nil
+}
+
+type GetCurrencyRequest struct {
+	Code string `json:"code"`
+}
+
+func DecodeGetCurrencyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := GetCurrencyRequest{Code: chi.URLParam(r, "code")}
+	if req.Code == "" {
+		return nil, badRequest("code required")
 	}
 	return req, nil
 }
@@ -80,16 +411,27 @@ func EncodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 	return json.NewEncoder(w).Encode(response)
 }
 
-func EncodeError(_ context.Context, err error, w http.ResponseWriter) {
+// EncodeError writes err as the error body: its stable code, message and
+// details. The text of a wrapped cause, such as an upstream failure, is
+// left out; the server's error handler logs it.
+func EncodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
-	code := http.StatusInternalServerError
-	if he, ok := err.(*httpError); ok {
-		code = he.Code
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		domainErr = domain.WrapError(domain.ErrInternal, err, nil)
+	}
+	code, ok := statusByCode[domainErr.Code]
+	if !ok {
+		code = http.StatusInternalServerError
 	}
 
 	w.WriteHeader(code)
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": false,
-		"error":   err.Error(),
+		"success":    false,
+		"code":       domainErr.Code,
+		"message":    domainErr.Message,
+		"details":    domainErr.Details,
+		"request_id": middleware.GetReqID(ctx),
 	})
 }
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBatchConversion(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate := domain.NewMoney(0.5, 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate, Timestamp: time.Now()}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour), service.WithMaxBatchSize(4))
+	defer server.Close()
+
+	t.Run("Partial success resolves each pair once", func(t *testing.T) {
+		body := `[
+			{"from": "USD", "to": "EUR", "amount": {"value": "10"}},
+			{"from": "usd", "to": "eur", "amount": {"value": "20"}},
+			{"from": "USDD", "to": "EUR", "amount": {"value": "30"}},
+			{"from": "EUR", "to": "GBP", "amount": {"value": "40"}}
+		]`
+		resp, err := http.Post(server.URL+"/api/v2/convert/batch", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result domain.BatchConversionResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.False(t, result.Success)
+		assert.Equal(t, 3, result.Succeeded)
+		assert.Equal(t, 1, result.Failed)
+
+		if assert.Len(t, result.Items, 4) {
+			assert.Equal(t, "5.00", result.Items[0].Result.Result.String())
+			assert.Equal(t, "10.00", result.Items[1].Result.Result.String())
+			assert.Nil(t, result.Items[2].Result)
+			assert.Equal(t, domain.ErrCodeUnsupportedCurrency, result.Items[2].Error.Code)
+			assert.Equal(t, "20.00", result.Items[3].Result.Result.String())
+		}
+		mockAPI.AssertNumberOfCalls(t, "Convert", 2)
+	})
+
+	t.Run("Batch size is capped", func(t *testing.T) {
+		item := `{"from": "USD", "to": "EUR", "amount": {"value": "1"}}`
+		body := "[" + strings.Repeat(item+",", 4) + item + "]"
+		resp, err := http.Post(server.URL+"/api/v2/convert/batch", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeBatchTooLarge, result["code"])
+	})
+
+	t.Run("Malformed item rejects the batch", func(t *testing.T) {
+		body := `[{"from": "USD", "to": "EUR", "amount": {"value": "1"}, "date": "yesterday"}]`
+		resp, err := http.Post(server.URL+"/api/v2/convert/batch", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/candles"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/scheduler"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCandleStore(t *testing.T) {
+	store := candles.NewStore("USD", 2*time.Hour)
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	observe := func(minute int, eur, gbp float64) {
+		store.Record(map[string]domain.Money{
+			"USD:EUR": domain.NewMoney(eur, 6),
+			"USD:GBP": domain.NewMoney(gbp, 6),
+		}, day.Add(time.Duration(minute)*time.Minute))
+	}
+	observe(60, 0.90, 0.80)
+	observe(62, 0.92, 0.80)
+	observe(64, 0.89, 0.80)
+	observe(70, 0.91, 0.80)
+
+	t.Run("Aggregates base pairs per resolution", func(t *testing.T) {
+		fiveMinute := store.Candles("USD", "EUR", domain.Resolution5m)
+		if assert.Len(t, fiveMinute, 2) {
+			assert.Equal(t, day.Add(time.Hour), fiveMinute[0].Start)
+			assert.Equal(t, "0.900000", fiveMinute[0].Open.String())
+			assert.Equal(t, "0.920000", fiveMinute[0].High.String())
+			assert.Equal(t, "0.890000", fiveMinute[0].Low.String())
+			assert.Equal(t, "0.890000", fiveMinute[0].Close.String())
+			assert.Equal(t, 3, fiveMinute[0].Observations)
+		}
+
+		hourly := store.Candles("USD", "EUR", domain.Resolution1h)
+		if assert.Len(t, hourly, 1) {
+			assert.Equal(t, "0.910000", hourly[0].Close.String())
+			assert.Equal(t, 4, hourly[0].Observations)
+		}
+	})
+
+	t.Run("Derives cross pairs from the same observations", func(t *testing.T) {
+		daily := store.Candles("EUR", "GBP", domain.Resolution1d)
+		if assert.Len(t, daily, 1) {
+			assert.Equal(t, day, daily[0].Start)
+			assert.Equal(t, "0.888888", daily[0].Open.String())
+			assert.Equal(t, "0.898876", daily[0].High.String())
+			assert.Equal(t, "0.869565", daily[0].Low.String())
+		}
+		assert.Empty(t, store.Candles("EUR", "JPY", domain.Resolution1d))
+	})
+
+	t.Run("Drops observations past retention", func(t *testing.T) {
+		observe(60*4, 0.95, 0.80)
+		hourly := store.Candles("USD", "EUR", domain.Resolution1h)
+		if assert.Len(t, hourly, 1) {
+			assert.Equal(t, day.Add(4*time.Hour), hourly[0].Start)
+		}
+	})
+}
+
+func TestCandlesEndpoint(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate := domain.NewMoney(0.9, 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	c := cache.NewMemoryCache(time.Hour)
+	store := candles.NewStore("USD", 0)
+	svc := service.NewConversionService(log.NewNopLogger(), mockAPI, c, service.WithCandleStore(store))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.NewScheduler(svc, c, store).StartRateUpdater(ctx)
+	cancel()
+
+	server := newMockServer(mockAPI, c, service.WithCandleStore(store))
+	defer server.Close()
+
+	t.Run("Serves candles recorded by the scheduler", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/rates/eur/usd/candles?resolution=1d")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result domain.CandleResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "EUR", result.From)
+		assert.Equal(t, domain.Resolution1d, result.Resolution)
+		if assert.NotEmpty(t, result.Candles) {
+			last := result.Candles[len(result.Candles)-1]
+			assert.Equal(t, "1.111111", last.Close.String())
+			// The startup base and adjustment passes observe the market once.
+			assert.Equal(t, 1, last.Observations)
+		}
+	})
+
+	t.Run("Rejects unknown resolutions", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/rates/EUR/USD/candles?resolution=1w")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// consensusMembers builds one mocked provider per rate, named a, b, c...;
+// an empty rate makes that provider fail.
+func consensusMembers(t *testing.T, rates ...string) []external.Provider {
+	members := make([]external.Provider, len(rates))
+	for i, value := range rates {
+		api := &MockExchangeRateAPI{}
+		if value == "" {
+			api.On("GetRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(domain.Money{}, errors.New("upstream down"))
+		} else {
+			rate, err := domain.NewMoneyFromString(value, 6)
+			assert.NoError(t, err)
+			api.On("GetRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(rate, nil)
+		}
+		members[i] = external.Provider{Name: string(rune('a' + i)), API: api}
+	}
+	return members
+}
+
+func TestConsensusProvider(t *testing.T) {
+	ctx := context.Background()
+	req := domain.ExchangeRate{From: "USD", To: "EUR", Rate: domain.NewMoney(100, 2)}
+
+	t.Run("Median drops outliers and reports the sources used", func(t *testing.T) {
+		consensus, err := external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{MaxDeviationBps: 50},
+			consensusMembers(t, "0.9200", "0.9210", "1.0500", "0.9205")...)
+		assert.NoError(t, err)
+
+		resp, err := consensus.Convert(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Equal(t, "0.920500", resp.Rate.String())
+		assert.Equal(t, "92.050000", resp.Amount.String())
+		assert.Equal(t, []string{"a", "b", "d"}, resp.Sources)
+	})
+
+	t.Run("Trimmed mean leaves out the extremes", func(t *testing.T) {
+		consensus, err := external.NewConsensus(log.NewNopLogger(),
+			external.ConsensusPolicy{Method: external.ConsensusTrimmedMean, TrimPercent: 25, MaxDeviationBps: 50},
+			consensusMembers(t, "0.9200", "0.9210", "0.9205", "0.9190", "1.0500")...)
+		assert.NoError(t, err)
+
+		rate, err := consensus.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.920250", rate.String())
+	})
+
+	t.Run("Keeps every answer without a deviation limit", func(t *testing.T) {
+		consensus, err := external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{},
+			consensusMembers(t, "0.9200", "1.0500")...)
+		assert.NoError(t, err)
+		resp, err := consensus.Convert(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.985000", resp.Rate.String())
+		assert.Equal(t, []string{"a", "b"}, resp.Sources)
+	})
+
+	t.Run("Fails below the minimum number of agreeing sources", func(t *testing.T) {
+		consensus, err := external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{MinSources: 2},
+			consensusMembers(t, "0.9200", "")...)
+		assert.NoError(t, err)
+		_, err = consensus.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.ErrorContains(t, err, "b: upstream down")
+
+		consensus, err = external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{MinSources: 1},
+			consensusMembers(t, "0.9200", "")...)
+		assert.NoError(t, err)
+		rate, err := consensus.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.920000", rate.String())
+	})
+
+	t.Run("Rejects invalid policies", func(t *testing.T) {
+		members := consensusMembers(t, "0.92", "0.93")
+		_, err := external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{Method: "mode"}, members...)
+		assert.Error(t, err)
+		_, err = external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{TrimPercent: 50}, members...)
+		assert.Error(t, err)
+		_, err = external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{MinSources: 3}, members...)
+		assert.Error(t, err)
+		_, err = external.ParseConsensusMethod("average")
+		assert.Error(t, err)
+	})
+
+	t.Run("Drops in as the service provider", func(t *testing.T) {
+		consensus, err := external.NewConsensus(log.NewNopLogger(), external.ConsensusPolicy{MaxDeviationBps: 50},
+			consensusMembers(t, "0.9200", "1.0500", "0.9210")...)
+		assert.NoError(t, err)
+		server := newMockServer(consensus, cache.NewMemoryCache(time.Hour))
+		defer server.Close()
+
+		resp, err := http.Post(server.URL+"/api/v2/convert", "application/json",
+			strings.NewReader(`{"from": "USD", "to": "EUR", "amount": {"value": "100"}}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result domain.ConversionResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "0.920500", result.MidRate.String())
+		assert.Equal(t, []string{"a", "c"}, result.Sources)
+
+		rateResp, err := http.Get(server.URL + "/api/v2/rates/USD/EUR")
+		assert.NoError(t, err)
+		defer rateResp.Body.Close()
+
+		var rate domain.PricedRate
+		assert.NoError(t, json.NewDecoder(rateResp.Body).Decode(&rate))
+		assert.Equal(t, http.StatusOK, rateResp.StatusCode)
+		assert.Equal(t, []string{"a", "c"}, rate.Sources)
+	})
+}
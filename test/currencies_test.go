@@ -0,0 +1,90 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/endpoint"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/transport"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockServer(api external.ExchangeRateAPI, c cache.Cache, opts ...service.Option) *httptest.Server {
+	logger := log.NewNopLogger()
+	svc := service.NewConversionService(logger, api, c, opts...)
+	return httptest.NewServer(transport.MakeHTTPHandler(endpoint.MakeConversionEndpoints(svc), logger))
+}
+
+func TestCurrenciesEndpoint(t *testing.T) {
+	c := cache.NewMemoryCache(time.Hour)
+	c.Set(service.RateSnapshotKey, &domain.RateCache{
+		BaseRates: map[string]domain.Money{
+			"USD:EUR": domain.NewMoney(0.92, 6),
+		},
+		Adjustments: map[string]domain.Money{},
+		LastFetch:   time.Now(),
+		LastUpdate:  time.Now(),
+	})
+
+	server := newMockServer(&MockExchangeRateAPI{}, c)
+	defer server.Close()
+
+	t.Run("Lists enabled currencies filtered by prefix", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/currencies?prefix=e")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body struct {
+			Currencies []domain.CurrencyInfo `json:"currencies"`
+			Count      int                   `json:"count"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, 1, body.Count)
+		assert.Equal(t, "EUR", body.Currencies[0].Code)
+		assert.Equal(t, "978", body.Currencies[0].NumericCode)
+		if assert.NotNil(t, body.Currencies[0].LatestRate) {
+			assert.Equal(t, "USD", body.Currencies[0].LatestRate.Base)
+			assert.Equal(t, "0.920000", body.Currencies[0].LatestRate.Rate.String())
+		}
+	})
+
+	t.Run("Filters by country", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/currencies?country=bt")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body struct {
+			Currencies []domain.CurrencyInfo `json:"currencies"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body.Currencies, 1)
+		assert.Equal(t, "INR", body.Currencies[0].Code)
+	})
+
+	t.Run("Single currency details", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/currencies/jpy")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body domain.CurrencyInfo
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 0, body.MinorUnits)
+		assert.Nil(t, body.LatestRate)
+	})
+
+	t.Run("Disabled currency is not found", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/currencies/KWD")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/currency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyRegistry(t *testing.T) {
+	t.Run("Ships the ISO 4217 table", func(t *testing.T) {
+		r := currency.NewISO4217Registry()
+
+		kwd, ok := r.Lookup("kwd")
+		assert.True(t, ok)
+		assert.Equal(t, "414", kwd.NumericCode)
+		assert.Equal(t, 3, kwd.MinorUnits)
+		assert.Contains(t, kwd.Countries, "KW")
+
+		dem, ok := r.Lookup("DEM")
+		assert.True(t, ok)
+		assert.False(t, dem.IsActive())
+	})
+
+	t.Run("Default enabled set", func(t *testing.T) {
+		r := currency.NewISO4217Registry()
+		assert.True(t, r.IsEnabled("USD"))
+		assert.False(t, r.IsEnabled("KWD"))
+		assert.Len(t, r.Enabled(), len(currency.DefaultEnabled))
+	})
+
+	t.Run("Enable and disable per deployment", func(t *testing.T) {
+		r := currency.NewISO4217Registry()
+		assert.NoError(t, r.SetEnabled([]string{"*"}))
+		assert.True(t, r.IsEnabled("KWD"))
+		assert.False(t, r.IsEnabled("DEM"))
+
+		assert.NoError(t, r.Disable([]string{"kwd"}))
+		assert.False(t, r.IsEnabled("KWD"))
+
+		assert.Error(t, r.SetEnabled([]string{"DEM"}))
+		assert.Error(t, r.SetEnabled([]string{"ABC"}))
+	})
+
+	t.Run("Overrides file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "overrides.yaml")
+		overrides := `
+- code: BTC
+  name: Bitcoin
+  symbol: "₿"
+  minor_units: 8
+- code: usd
+  symbol: "US$"
+- code: GBP
+  status: withdrawn
+`
+		assert.NoError(t, os.WriteFile(path, []byte(overrides), 0o644))
+
+		r := currency.NewISO4217Registry()
+		assert.NoError(t, r.LoadOverrides(path))
+
+		btc, ok := r.Lookup("BTC")
+		assert.True(t, ok)
+		assert.Equal(t, 8, btc.MinorUnits)
+		assert.NoError(t, r.SetEnabled([]string{"BTC", "USD"}))
+
+		usd, _ := r.Lookup("USD")
+		assert.Equal(t, "US$", usd.Symbol)
+		assert.Equal(t, "840", usd.NumericCode)
+
+		assert.False(t, r.IsEnabled("GBP"))
+	})
+
+	t.Run("JSON overrides are accepted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "overrides.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`[{"code": "JPY", "minor_units": 2}]`), 0o644))
+
+		r := currency.NewISO4217Registry()
+		assert.NoError(t, r.LoadOverrides(path))
+		jpy, _ := r.Lookup("JPY")
+		assert.Equal(t, 2, jpy.MinorUnits)
+	})
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fixtureDate parses the YYYY-MM-DD dates used by the provider fixtures.
+func fixtureDate(s string) time.Time {
+	date, _ := time.Parse("2006-01-02", s)
+	return date
+}
+
+func TestECBProvider(t *testing.T) {
+	ctx := context.Background()
+	provider := external.NewECBProvider("testdata/ecb-daily.xml", "testdata/ecb-hist.xml", time.Second)
+
+	t.Run("Rebases the latest rates to any base", func(t *testing.T) {
+		rate, err := provider.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.861475", rate.String())
+
+		rate, err = provider.GetRate(ctx, "eur", "usd", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "1.160800", rate.String())
+
+		resp, err := provider.Convert(ctx, domain.ExchangeRate{From: "USD", To: "JPY", Rate: domain.NewMoney(100, 2)})
+		assert.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Equal(t, "147.501723", resp.Rate.String())
+		assert.Equal(t, "14750.172300", resp.Amount.String())
+		assert.Equal(t, fixtureDate("2025-08-21"), resp.Timestamp)
+	})
+
+	t.Run("Reads past dates from the history feed", func(t *testing.T) {
+		rate, err := provider.GetRate(ctx, "USD", "JPY", fixtureDate("2025-08-20"))
+		assert.NoError(t, err)
+		assert.Equal(t, "147.390110", rate.String())
+
+		// Sunday falls back to Friday's fixing.
+		resp, err := provider.Convert(ctx, domain.ExchangeRate{From: "USD", To: "GBP", Rate: domain.NewMoney(1, 6), Date: fixtureDate("2025-08-17")})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.739623", resp.Rate.String())
+		assert.Equal(t, fixtureDate("2025-08-15"), resp.Timestamp)
+	})
+
+	t.Run("Reports rates it does not publish as not found", func(t *testing.T) {
+		_, err := provider.GetRate(ctx, "USD", "INR", fixtureDate("2025-08-19"))
+		assert.True(t, errors.Is(err, domain.ErrRateNotFound))
+		_, err = provider.GetRate(ctx, "USD", "CHF", time.Time{})
+		assert.True(t, errors.Is(err, domain.ErrRateNotFound))
+		_, err = provider.GetRate(ctx, "USD", "EUR", fixtureDate("2025-08-01"))
+		assert.True(t, errors.Is(err, domain.ErrRateNotFound))
+	})
+
+	t.Run("Fails on a missing or malformed feed", func(t *testing.T) {
+		_, err := external.NewECBProvider("testdata/missing.xml", "", time.Second).GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, domain.ErrRateNotFound))
+		_, err = external.NewECBProvider("testdata/ecb-bad.xml", "", time.Second).GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.ErrorContains(t, err, "invalid eurofxref")
+	})
+
+	t.Run("Fetches a URL once per refresh interval", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			http.ServeFile(w, r, "testdata/ecb-daily.xml")
+		}))
+		defer upstream.Close()
+
+		provider := external.NewECBProvider(upstream.URL+"/eurofxref-daily.xml", "", time.Second)
+		for i := 0; i < 3; i++ {
+			rate, err := provider.GetRate(ctx, "GBP", "INR", time.Time{})
+			assert.NoError(t, err)
+			assert.Equal(t, "117.311163", rate.String())
+		}
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("Backs off a feed that fails to load", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer upstream.Close()
+
+		provider := external.NewECBProvider(upstream.URL+"/eurofxref-daily.xml", "", time.Second)
+		for i := 0; i < 3; i++ {
+			_, err := provider.GetRate(ctx, "USD", "EUR", time.Time{})
+			assert.ErrorContains(t, err, "502")
+		}
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("A caller giving up does not fail the shared read", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			http.ServeFile(w, r, "testdata/ecb-daily.xml")
+		}))
+		defer upstream.Close()
+
+		provider := external.NewECBProvider(upstream.URL+"/eurofxref-daily.xml", "", time.Second)
+		short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := provider.GetRate(short, "USD", "EUR", time.Time{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		rate, err := provider.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.861475", rate.String())
+	})
+
+	t.Run("Registry fails over on pairs the ECB does not quote", func(t *testing.T) {
+		backup := &MockExchangeRateAPI{}
+		chf, _ := domain.NewMoneyFromString("0.80", 6)
+		backup.On("GetRate", mock.Anything, "USD", "CHF", mock.Anything).Return(chf, nil)
+
+		registry, err := external.NewRegistry(log.NewNopLogger(), time.Minute,
+			external.Provider{Name: "ecb", API: provider}, external.Provider{Name: "backup", API: backup})
+		assert.NoError(t, err)
+
+		rate, err := registry.GetRate(ctx, "USD", "CHF", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.800000", rate.String())
+		assert.True(t, registry.Health()[0].Healthy)
+	})
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/pricing"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestFeeSchedule(t *testing.T) *pricing.FeeSchedule {
+	money := func(s string) *domain.Money {
+		m, err := domain.NewMoneyFromString(s, 2)
+		assert.NoError(t, err)
+		return &m
+	}
+	schedule, err := pricing.NewFeeSchedule([]pricing.Fee{
+		{Name: "transfer", From: "usd", Side: pricing.SideSource, Fixed: *money("1.50"), Percent: *money("0.25"), Min: money("2"), Max: money("25")},
+		{Name: "payout", To: "EUR", Side: pricing.SideTarget, Percent: *money("1"), Min: money("0.50")},
+		{Name: "gbp_only", From: "GBP", Side: pricing.SideSource, Fixed: *money("5")},
+	})
+	assert.NoError(t, err)
+	return schedule
+}
+
+func TestFeeSchedule(t *testing.T) {
+	t.Run("Rejects invalid fees", func(t *testing.T) {
+		two, one := domain.NewMoney(2, 0), domain.NewMoney(1, 0)
+		_, err := pricing.NewFeeSchedule([]pricing.Fee{{Name: "x", Side: "both"}})
+		assert.Error(t, err)
+		_, err = pricing.NewFeeSchedule([]pricing.Fee{{Side: pricing.SideSource}})
+		assert.Error(t, err)
+		_, err = pricing.NewFeeSchedule([]pricing.Fee{{Name: "x", Side: pricing.SideTarget, Min: &two, Max: &one}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Rounds each fee to minor units", func(t *testing.T) {
+		base, _ := domain.NewMoneyFromString("1234.5", 2)
+		items := newTestFeeSchedule(t).Apply("USD", "JPY", pricing.SideSource, base)
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, "transfer", items[0].Name)
+			assert.Equal(t, "USD", items[0].Currency)
+			assert.Equal(t, "4.59", items[0].Amount.String())
+		}
+	})
+
+	t.Run("Solves for the gross that leaves a net", func(t *testing.T) {
+		net, _ := domain.NewMoneyFromString("495", 2)
+		gross, items, ok := newTestFeeSchedule(t).GrossFor("USD", "EUR", net)
+		assert.True(t, ok)
+		assert.Equal(t, "500.00", gross.String())
+		assert.Equal(t, "5.00", pricing.Total(items, 2).String())
+
+		all, _ := domain.NewMoneyFromString("100", 2)
+		greedy, err := pricing.NewFeeSchedule([]pricing.Fee{{Name: "all", Side: pricing.SideTarget, Percent: all}})
+		assert.NoError(t, err)
+		_, _, ok = greedy.GrossFor("USD", "EUR", net)
+		assert.False(t, ok)
+	})
+}
+
+func TestConversionFees(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate, _ := domain.NewMoneyFromString("0.5", 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour), service.WithFees(newTestFeeSchedule(t)))
+	defer server.Close()
+
+	convert := func(t *testing.T, body string) (*http.Response, domain.ConversionResponse) {
+		resp, err := http.Post(server.URL+"/api/v2/convert", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var result domain.ConversionResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+
+	t.Run("Itemises source and target fees", func(t *testing.T) {
+		resp, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "100"}}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		if assert.Len(t, result.Fees, 2) {
+			assert.Equal(t, domain.FeeItem{Name: "transfer", Side: "source", Currency: "USD", Amount: result.Fees[0].Amount}, result.Fees[0])
+			assert.Equal(t, "2.00", result.Fees[0].Amount.String())
+			assert.Equal(t, "EUR", result.Fees[1].Currency)
+			assert.Equal(t, "0.50", result.Fees[1].Amount.String())
+		}
+		assert.Equal(t, "50.00", result.GrossAmount.String())
+		assert.Equal(t, "49.50", result.NetAmount.String())
+		assert.Equal(t, "102.00", result.TotalCharged.String())
+	})
+
+	t.Run("Caps percentage fees", func(t *testing.T) {
+		_, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "20000"}}`)
+		if assert.Len(t, result.Fees, 2) {
+			assert.Equal(t, "25.00", result.Fees[0].Amount.String())
+			assert.Equal(t, "100.00", result.Fees[1].Amount.String())
+		}
+		assert.Equal(t, "9900.00", result.NetAmount.String())
+		assert.Equal(t, "20025.00", result.TotalCharged.String())
+	})
+
+	t.Run("Fixed target covers target fees", func(t *testing.T) {
+		_, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "49.50"}, "fixed_side": "to"}`)
+		assert.Equal(t, "49.50", result.NetAmount.String())
+		assert.Equal(t, "50.00", result.GrossAmount.String())
+		assert.Equal(t, "100.00", result.Result.String())
+		assert.Equal(t, "102.00", result.TotalCharged.String())
+	})
+
+	t.Run("Both modes charge target fees on gross", func(t *testing.T) {
+		_, forward := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "1000"}}`)
+		assert.Equal(t, "500.00", forward.GrossAmount.String())
+		assert.Equal(t, "495.00", forward.NetAmount.String())
+
+		_, target := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "495.00"}, "fixed_side": "to"}`)
+		assert.Equal(t, forward.GrossAmount.String(), target.GrossAmount.String())
+		assert.Equal(t, forward.NetAmount.String(), target.NetAmount.String())
+		assert.Equal(t, "1000.00", target.Result.String())
+		if assert.Len(t, target.Fees, 2) && assert.Len(t, forward.Fees, 2) {
+			assert.Equal(t, "5.00", target.Fees[1].Amount.String())
+			assert.Equal(t, forward.Fees[1].Amount.String(), target.Fees[1].Amount.String())
+		}
+	})
+
+	t.Run("Rejects amounts that do not cover the fees", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/api/v2/convert", "application/json",
+			strings.NewReader(`{"from": "USD", "to": "EUR", "amount": {"value": "0.50"}}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
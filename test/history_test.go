@@ -0,0 +1,94 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateHistory(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, -10)
+	missing := start.AddDate(0, 0, 2)
+
+	mockAPI := &MockExchangeRateAPI{}
+	rate := domain.NewMoney(0.9, 6)
+	mockAPI.On("Convert", mock.Anything, mock.MatchedBy(func(req domain.ExchangeRate) bool {
+		return req.Date.Equal(missing)
+	})).Return(domain.ExchangeRateResponse{Success: false}, errors.New("no data for date"))
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour), service.WithMaxHistoryPoints(5))
+	defer server.Close()
+
+	get := func(t *testing.T, query string) (*http.Response, domain.RateHistoryResponse) {
+		resp, err := http.Get(server.URL + "/api/v2/rates/usd/eur/history?" + query)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result domain.RateHistoryResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+
+	t.Run("Daily series keeps failed days as gaps", func(t *testing.T) {
+		end := start.AddDate(0, 0, 3)
+		resp, result := get(t, "start="+start.Format("2006-01-02")+"&end="+end.Format("2006-01-02"))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.False(t, result.Success)
+		assert.Equal(t, "USD", result.From)
+		assert.Equal(t, 1, result.Gaps)
+
+		if assert.Len(t, result.Points, 4) {
+			assert.Equal(t, start.Format("2006-01-02"), result.Points[0].Date)
+			assert.Equal(t, "0.900000", result.Points[0].Rate.String())
+			assert.Nil(t, result.Points[2].Rate)
+			assert.Equal(t, domain.ErrCodeUpstreamUnavailable, result.Points[2].Error.Code)
+			assert.Equal(t, end.Format("2006-01-02"), result.Points[3].Date)
+		}
+	})
+
+	t.Run("Weekly interval samples every seventh day", func(t *testing.T) {
+		resp, result := get(t, "start="+start.Format("2006-01-02")+"&end="+today.Format("2006-01-02")+"&interval=week")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, result.Success)
+		if assert.Len(t, result.Points, 2) {
+			assert.Equal(t, start.AddDate(0, 0, 7).Format("2006-01-02"), result.Points[1].Date)
+		}
+	})
+
+	t.Run("Rejects ranges over the point limit", func(t *testing.T) {
+		resp, _ := get(t, "start="+start.Format("2006-01-02"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Rejects bad intervals and inverted ranges", func(t *testing.T) {
+		resp, _ := get(t, "start="+start.Format("2006-01-02")+"&interval=hour")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = get(t, "start="+today.Format("2006-01-02")+"&end="+start.Format("2006-01-02"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestHistoryMonthlyDates(t *testing.T) {
+	req := domain.RateHistoryRequest{
+		Start:    time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+		Interval: domain.IntervalMonth,
+	}
+	var got []string
+	for _, date := range req.Dates() {
+		got = append(got, date.Format("2006-01-02"))
+	}
+	assert.Equal(t, []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"}, got)
+}
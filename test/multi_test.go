@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func postMulti(t *testing.T, url, body string) (*http.Response, domain.MultiConversionResponse) {
+	resp, err := http.Post(url+"/api/v2/convert/multi", "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result domain.MultiConversionResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return resp, result
+}
+
+func TestMultiConversion(t *testing.T) {
+	t.Run("Prices every target from the published snapshot", func(t *testing.T) {
+		asOf := time.Now().Add(-2 * time.Minute).UTC().Truncate(time.Second)
+		c := cache.NewMemoryCache(time.Hour)
+		c.Set(service.RateSnapshotKey, &domain.RateCache{
+			BaseRates: map[string]domain.Money{
+				"USD:EUR": domain.NewMoney(0.9, 6),
+				"USD:JPY": domain.NewMoney(150, 6),
+				"USD:GBP": domain.NewMoney(0.8, 6),
+			},
+			Adjustments: map[string]domain.Money{},
+			LastFetch:   asOf,
+			LastUpdate:  asOf,
+		})
+
+		mockAPI := &MockExchangeRateAPI{}
+		server := newMockServer(mockAPI, c)
+		defer server.Close()
+
+		resp, result := postMulti(t, server.URL, `{"from": "eur", "to": ["JPY", "gbp", "USD", "JPY"], "amount": {"value": "100"}}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, asOf.Equal(result.RatesAsOf))
+
+		if assert.Len(t, result.Results, 3) {
+			assert.Equal(t, "JPY", result.Results[0].To)
+			assert.Equal(t, "166.666666", result.Results[0].Rate.String())
+			assert.Equal(t, "16666", result.Results[0].Result.String())
+			assert.Equal(t, "GBP", result.Results[1].To)
+			assert.Equal(t, "88.88", result.Results[1].Result.String())
+			assert.Equal(t, "USD", result.Results[2].To)
+			assert.Equal(t, "111.11", result.Results[2].Result.String())
+		}
+		mockAPI.AssertNotCalled(t, "Convert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fetches each leg once without a snapshot", func(t *testing.T) {
+		mockAPI := &MockExchangeRateAPI{}
+		rate := domain.NewMoney(2, 6)
+		mockAPI.On("Convert", mock.Anything, mock.Anything).
+			Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+		server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+		defer server.Close()
+
+		resp, result := postMulti(t, server.URL, `{"from": "USD", "to": ["EUR", "GBP"], "amount": {"value": "5"}}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, result.Results, 2)
+		mockAPI.AssertNumberOfCalls(t, "Convert", 2)
+	})
+
+	t.Run("Rejects the source as a target", func(t *testing.T) {
+		server := newMockServer(&MockExchangeRateAPI{}, cache.NewMemoryCache(time.Hour))
+		defer server.Close()
+
+		resp, _ := postMulti(t, server.URL, `{"from": "USD", "to": ["EUR", "usd"], "amount": {"value": "5"}}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPeriodRates(t *testing.T) {
+	// A Monday two to three weeks back, so the range Monday..Monday spans
+	// one weekend and stays inside the historical window.
+	monday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -14)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, -1)
+	}
+	day := func(offset int) string { return monday.AddDate(0, 0, offset).Format("2006-01-02") }
+
+	// Wednesday (offset 2) is a holiday without a rate.
+	rates := map[int]string{0: "1.0", 1: "1.1", 3: "1.2", 4: "1.3", 7: "1.45"}
+	mockAPI := &MockExchangeRateAPI{}
+	for offset, value := range rates {
+		date := monday.AddDate(0, 0, offset)
+		rate, err := domain.NewMoneyFromString(value, 6)
+		assert.NoError(t, err)
+		mockAPI.On("Convert", mock.Anything, mock.MatchedBy(func(req domain.ExchangeRate) bool {
+			return req.Date.Equal(date)
+		})).Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+	}
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: false}, errors.New("no data for date"))
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+	defer server.Close()
+
+	get := func(t *testing.T, path string, v interface{}) *http.Response {
+		resp, err := http.Get(server.URL + "/api/v2/rates/USD/EUR/" + path)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		return resp
+	}
+
+	t.Run("Average states the dates it used", func(t *testing.T) {
+		var result domain.PeriodAverageResponse
+		resp := get(t, "average?start="+day(0)+"&end="+day(7)+"&scale=1&rounding=half_even", &result)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "1.2", result.Rate.String())
+		assert.Equal(t, domain.WeekendsExclude, result.Weekends)
+		assert.Equal(t, []string{day(0), day(1), day(3), day(4), day(7)}, result.RateDates)
+		assert.Equal(t, []string{day(5), day(6)}, result.SkippedDates)
+		assert.Equal(t, []string{day(2)}, result.MissingDates)
+	})
+
+	t.Run("Average honours scale and rounding", func(t *testing.T) {
+		var result domain.PeriodAverageResponse
+		get(t, "average?start="+day(0)+"&end="+day(7)+"&scale=8", &result)
+		assert.Equal(t, "1.21000000", result.Rate.String())
+
+		get(t, "average?start="+day(0)+"&end="+day(7)+"&scale=1&rounding=ceiling", &result)
+		assert.Equal(t, "1.3", result.Rate.String())
+	})
+
+	t.Run("Including weekends reports them as missing", func(t *testing.T) {
+		var result domain.PeriodAverageResponse
+		get(t, "average?start="+day(0)+"&end="+day(7)+"&weekends=include", &result)
+		assert.Empty(t, result.SkippedDates)
+		assert.Equal(t, []string{day(2), day(5), day(6)}, result.MissingDates)
+	})
+
+	t.Run("Closing rate steps back over the weekend", func(t *testing.T) {
+		var result domain.ClosingRateResponse
+		resp := get(t, "closing?date="+day(6)+"&scale=2", &result)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, day(6), result.PeriodEnd)
+		assert.Equal(t, day(4), result.RateDate)
+		assert.Equal(t, "1.30", result.Rate.String())
+		assert.Equal(t, []string{day(6), day(5)}, result.SkippedDates)
+	})
+
+	t.Run("Closing rate steps back over a holiday", func(t *testing.T) {
+		var result domain.ClosingRateResponse
+		get(t, "closing?date="+day(2), &result)
+		assert.Equal(t, day(1), result.RateDate)
+		assert.Equal(t, []string{day(2)}, result.SkippedDates)
+	})
+
+	t.Run("Rejects periods that have not ended", func(t *testing.T) {
+		var result map[string]interface{}
+		resp := get(t, "average?period="+time.Now().UTC().AddDate(0, 1, 0).Format("2006-01"), &result)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeDateOutOfRange, result["code"])
+	})
+}
+
+func TestPeriodAverageLimits(t *testing.T) {
+	rate, err := domain.NewMoneyFromString("1.25", 6)
+	assert.NoError(t, err)
+	mockAPI := &MockExchangeRateAPI{}
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	average := func(t *testing.T, opts []service.Option, query string) (*http.Response, map[string]interface{}) {
+		server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour), opts...)
+		defer server.Close()
+		resp, err := http.Get(server.URL + "/api/v2/rates/USD/EUR/average?" + query)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+
+	// The last quarter that has ended starts more than MaxHistoricalDays
+	// back whenever today is late in the current one.
+	now := time.Now().UTC()
+	quarter := (int(now.Month())-1)/3 + 1
+	year := now.Year()
+	if quarter--; quarter == 0 {
+		quarter, year = 4, year-1
+	}
+	lastQuarter := fmt.Sprintf("%d-Q%d", year, quarter)
+
+	t.Run("Averages a quarter that has ended", func(t *testing.T) {
+		resp, result := average(t, nil, "period="+lastQuarter)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "1.250000", result["rate"].(map[string]interface{})["value"])
+		assert.Empty(t, result["missing_dates"])
+	})
+
+	t.Run("Caps the days fetched", func(t *testing.T) {
+		resp, result := average(t, []service.Option{service.WithMaxPeriodDays(10)}, "period="+lastQuarter)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeDateOutOfRange, result["code"])
+	})
+
+	t.Run("Rejects periods older than a year", func(t *testing.T) {
+		resp, result := average(t, nil, "period="+now.AddDate(-2, 0, 0).Format("2006-01"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeDateOutOfRange, result["code"])
+	})
+}
+
+func TestParsePeriod(t *testing.T) {
+	start, end, err := domain.ParsePeriod("2025-q1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-01-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2025-03-31", end.Format("2006-01-02"))
+
+	start, end, err = domain.ParsePeriod("2024-02")
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-02-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2024-02-29", end.Format("2006-01-02"))
+
+	for _, bad := range []string{"2025-Q5", "2025", "25-01", "2025-13"} {
+		_, _, err := domain.ParsePeriod(bad)
+		assert.Error(t, err, bad)
+	}
+}
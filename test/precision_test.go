@@ -2,6 +2,9 @@ package test
 
 import (
 	"context"
+	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -162,7 +165,8 @@ func TestCurrencyConversionPrecision(t *testing.T) {
 		assert.True(t, resp.Success)
 
 		expectedAmount := domain.NewMoney(1234.567, 6)
-		assert.InDelta(t, expectedAmount.ToFloat(), resp.Result.ToFloat(), 0.000001)
+		assert.InDelta(t, expectedAmount.ToFloat(), resp.UnroundedResult.ToFloat(), 0.000001)
+		assert.Equal(t, "1234.56", resp.Result.String())
 	})
 
 	t.Run("Precision maintained in cache", func(t *testing.T) {
@@ -193,3 +197,255 @@ func TestCurrencyConversionPrecision(t *testing.T) {
 		assert.Equal(t, resp1.Result.Scale, resp2.Result.Scale)
 	})
 }
+
+func TestMoneyArbitraryPrecision(t *testing.T) {
+	t.Run("Treasury amount times JPY rate does not wrap", func(t *testing.T) {
+		amount := domain.NewMoney(10000000, 6)
+		rate := domain.NewMoney(157.123456, 6)
+		result := amount.Multiply(rate)
+
+		assert.Equal(t, "1571234560000000", result.Amount.String())
+		assert.Equal(t, domain.DefaultScale, result.Scale)
+		assert.NoError(t, result.Validate())
+	})
+
+	t.Run("Division keeps precision past int64", func(t *testing.T) {
+		dividend := domain.NewMoney(9000000000000, 6)
+		divisor := domain.NewMoney(0.000001, 6)
+		result := dividend.Divide(divisor)
+
+		assert.Equal(t, "9000000000000000000000000", result.Amount.String())
+		_, err := result.Int64()
+		assert.ErrorIs(t, err, domain.ErrOverflow)
+	})
+
+	t.Run("Digits beyond MaxDigits are rejected", func(t *testing.T) {
+		var m domain.Money
+		err := m.UnmarshalJSON([]byte(`{"amount": 123456789012345678901234567890123456789012, "scale": 2}`))
+
+		var overflow *domain.OverflowError
+		assert.ErrorAs(t, err, &overflow)
+	})
+
+	t.Run("Non-finite floats are reported", func(t *testing.T) {
+		_, err := domain.NewMoneyFromFloat(math.Inf(1), 2)
+		assert.ErrorIs(t, err, domain.ErrOverflow)
+	})
+}
+
+func TestMoneyStringParsing(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		scale       int
+		expectedStr string
+		expectErr   bool
+	}{
+		{name: "Tenth is exact", value: "0.1", scale: 6, expectedStr: "0.100000"},
+		{name: "Seventeen digit amount", value: "12345678901234567", scale: 2, expectedStr: "12345678901234567.00"},
+		{name: "Negative with leading zeros", value: "-000123.4500", scale: 2, expectedStr: "-123.45"},
+		{name: "Explicit plus sign", value: "+7", scale: 0, expectedStr: "7"},
+		{name: "Exponent notation", value: "1.5e3", scale: 2, expectedStr: "1500.00"},
+		{name: "Negative exponent", value: "25E-4", scale: 6, expectedStr: "0.002500"},
+		{name: "Eighteen decimal places", value: "0.000000000000000001", scale: 18, expectedStr: "0.000000000000000001"},
+		{name: "Too many fractional digits", value: "1.234", scale: 2, expectErr: true},
+		{name: "Scale beyond max", value: "1", scale: 19, expectErr: true},
+		{name: "Not a number", value: "abc", scale: 2, expectErr: true},
+		{name: "Lone dot", value: ".", scale: 2, expectErr: true},
+		{name: "Missing exponent digits", value: "1e", scale: 2, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money, err := domain.NewMoneyFromString(tt.value, tt.scale)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStr, money.String())
+		})
+	}
+
+	t.Run("Truncation when a rounding mode is given", func(t *testing.T) {
+		money, err := domain.ParseMoney("1.239", 2, domain.RoundDown)
+		assert.NoError(t, err)
+		assert.Equal(t, "1.23", money.String())
+	})
+
+	t.Run("JSON value keeps every digit sent", func(t *testing.T) {
+		var money domain.Money
+		assert.NoError(t, json.Unmarshal([]byte(`{"value": "0.12345678"}`), &money))
+		assert.Equal(t, 8, money.Scale)
+		assert.Equal(t, "0.12345678", money.String())
+	})
+
+	t.Run("JSON value rejects digits beyond explicit scale", func(t *testing.T) {
+		var money domain.Money
+		assert.Error(t, json.Unmarshal([]byte(`{"value": "1.005", "scale": 2}`), &money))
+	})
+}
+
+func TestMoneyRoundingModes(t *testing.T) {
+	tests := []struct {
+		value    string
+		mode     domain.RoundingMode
+		expected string
+	}{
+		{"2.345", domain.RoundDown, "2.34"},
+		{"-2.345", domain.RoundDown, "-2.34"},
+		{"2.345", domain.RoundHalfUp, "2.35"},
+		{"-2.345", domain.RoundHalfUp, "-2.35"},
+		{"2.345", domain.RoundHalfDown, "2.34"},
+		{"2.3451", domain.RoundHalfDown, "2.35"},
+		{"2.345", domain.RoundHalfEven, "2.34"},
+		{"2.355", domain.RoundHalfEven, "2.36"},
+		{"-2.355", domain.RoundHalfEven, "-2.36"},
+		{"2.341", domain.RoundCeiling, "2.35"},
+		{"-2.349", domain.RoundCeiling, "-2.34"},
+		{"2.349", domain.RoundFloor, "2.34"},
+		{"-2.341", domain.RoundFloor, "-2.35"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String()+" "+tt.value, func(t *testing.T) {
+			money, err := domain.NewMoneyFromString(tt.value, 4)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, money.ConvertToScale(2, tt.mode).String())
+		})
+	}
+
+	t.Run("Multiply rounds past DefaultScale", func(t *testing.T) {
+		amount, _ := domain.NewMoneyFromString("0.000005", 6)
+		half, _ := domain.NewMoneyFromString("0.5", 1)
+		assert.Equal(t, "0.000002", amount.Multiply(half).String())
+		assert.Equal(t, "0.000003", amount.Multiply(half, domain.RoundHalfUp).String())
+	})
+
+	t.Run("Divide rounds the quotient", func(t *testing.T) {
+		one := domain.NewMoney(1, 0)
+		three := domain.NewMoney(3, 0)
+		assert.Equal(t, "0.333333", one.Divide(three).String())
+		assert.Equal(t, "0.333334", one.Divide(three, domain.RoundCeiling).String())
+		assert.Equal(t, "-0.333334", one.Neg().Divide(three, domain.RoundFloor).String())
+	})
+
+	t.Run("Unnecessary rounding panics when digits would be dropped", func(t *testing.T) {
+		one := domain.NewMoney(1, 0)
+		three := domain.NewMoney(3, 0)
+		assert.Equal(t, "0.500000", one.Divide(domain.NewMoney(2, 0), domain.RoundUnnecessary).String())
+		assert.PanicsWithError(t, "money Quo: money result needs rounding", func() {
+			one.Divide(three, domain.RoundUnnecessary)
+		})
+		amount, _ := domain.NewMoneyFromString("2.345", 3)
+		assert.Panics(t, func() { amount.ConvertToScale(2, domain.RoundUnnecessary) })
+	})
+
+	t.Run("Arithmetic results are bounded only by Validate", func(t *testing.T) {
+		large, err := domain.NewMoneyFromString("9"+strings.Repeat("0", 30), 0)
+		assert.NoError(t, err)
+		product := large.MultiplyExact(large)
+		assert.ErrorIs(t, product.Validate(), domain.ErrOverflow)
+	})
+
+	t.Run("Mode names round-trip", func(t *testing.T) {
+		mode, err := domain.ParseRoundingMode("Half-Even")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoundHalfEven, mode)
+
+		_, err = domain.ParseRoundingMode("sideways")
+		assert.Error(t, err)
+	})
+}
+
+func TestConversionMinorUnits(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	svc := service.NewConversionService(log.NewNopLogger(), mockAPI, cache.NewMemoryCache(time.Hour))
+
+	rate := domain.NewMoney(157.123456, 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).Return(domain.ExchangeRateResponse{
+		Success:   true,
+		Rate:      rate,
+		Amount:    rate,
+		Timestamp: time.Now(),
+	}, nil)
+
+	resp, err := svc.ConvertCurrency(context.Background(), &domain.ConversionRequest{
+		From:     "USD",
+		To:       "JPY",
+		Amount:   domain.NewMoney(10.5, 2),
+		Rounding: domain.RoundHalfUp,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1649.796288", resp.UnroundedResult.String())
+	assert.Equal(t, "1650", resp.Result.String())
+	assert.Equal(t, 0, resp.Result.Scale)
+	assert.Equal(t, domain.RoundHalfUp, resp.Rounding)
+}
+
+func TestHistoricalConversion(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	svc := service.NewConversionService(log.NewNopLogger(), mockAPI, cache.NewMemoryCache(time.Hour))
+
+	pastDate := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -30)
+	pastRate := domain.NewMoney(0.9, 6)
+	liveRate := domain.NewMoney(0.95, 6)
+
+	// A Saturday the provider answers with the Friday before.
+	saturday := pastDate.AddDate(0, 0, -7)
+	for saturday.Weekday() != time.Saturday {
+		saturday = saturday.AddDate(0, 0, -1)
+	}
+	friday := saturday.AddDate(0, 0, -1)
+	fridayRate := domain.NewMoney(0.88, 6)
+	mockAPI.On("Convert", mock.Anything, mock.MatchedBy(func(req domain.ExchangeRate) bool {
+		return req.Date.Equal(saturday)
+	})).Return(domain.ExchangeRateResponse{Success: true, Rate: fridayRate, Amount: fridayRate, Timestamp: friday}, nil)
+
+	mockAPI.On("Convert", mock.Anything, mock.MatchedBy(func(req domain.ExchangeRate) bool {
+		return req.Date.Equal(pastDate)
+	})).Return(domain.ExchangeRateResponse{Success: true, Rate: pastRate, Amount: pastRate}, nil)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: liveRate, Amount: liveRate}, nil)
+
+	t.Run("Past date uses that day's rate", func(t *testing.T) {
+		resp, err := svc.ConvertCurrency(context.Background(), &domain.ConversionRequest{
+			From:   "USD",
+			To:     "EUR",
+			Amount: domain.NewMoney(100, 2),
+			Date:   pastDate,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pastRate.String(), resp.Rate.String())
+		assert.Equal(t, pastDate.Format("2006-01-02"), resp.RateDate)
+		assert.True(t, resp.Historical)
+	})
+
+	t.Run("Reports the day the provider priced", func(t *testing.T) {
+		resp, err := svc.ConvertCurrency(context.Background(), &domain.ConversionRequest{
+			From:   "USD",
+			To:     "EUR",
+			Amount: domain.NewMoney(100, 2),
+			Date:   saturday,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, fridayRate.String(), resp.Rate.String())
+		assert.Equal(t, friday.Format("2006-01-02"), resp.RateDate)
+	})
+
+	t.Run("No date uses the live rate", func(t *testing.T) {
+		resp, err := svc.ConvertCurrency(context.Background(), &domain.ConversionRequest{
+			From:   "USD",
+			To:     "EUR",
+			Amount: domain.NewMoney(100, 2),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, liveRate.String(), resp.Rate.String())
+		assert.Equal(t, time.Now().UTC().Format("2006-01-02"), resp.RateDate)
+		assert.False(t, resp.Historical)
+	})
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/pricing"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPricingEngine(t *testing.T) *pricing.Engine {
+	tier, err := domain.NewMoneyFromString("10000", 2)
+	assert.NoError(t, err)
+	engine, err := pricing.NewEngine(10, map[string]int{"retail": 25, "VIP": -40}, []pricing.PairRule{
+		{From: "usd", To: "inr", SpreadBps: 50, Tiers: []pricing.Tier{{MinAmount: tier, SpreadBps: 30}}},
+	})
+	assert.NoError(t, err)
+	return engine
+}
+
+func TestPricingEngine(t *testing.T) {
+	engine := newTestPricingEngine(t)
+	small := domain.NewMoney(100, 2)
+	large := domain.NewMoney(25000, 2)
+
+	t.Run("Spreads by pair, tier and segment", func(t *testing.T) {
+		assert.Equal(t, 50, engine.SpreadBps("USD", "INR", small, ""))
+		assert.Equal(t, 30, engine.SpreadBps("USD", "INR", large, ""))
+		assert.Equal(t, 75, engine.SpreadBps("USD", "INR", small, "retail"))
+		assert.Equal(t, 0, engine.SpreadBps("USD", "INR", large, "vip"))
+		assert.Equal(t, 10, engine.SpreadBps("EUR", "GBP", small, ""))
+		assert.Equal(t, 10, engine.SpreadBps("INR", "USD", small, ""))
+		assert.True(t, engine.HasSegment("Retail"))
+		assert.False(t, engine.HasSegment("wholesale"))
+	})
+
+	t.Run("Rounds bid down and ask up", func(t *testing.T) {
+		mid, _ := domain.NewMoneyFromString("83.123456", 6)
+		priced := engine.Price(mid, "USD", "INR", small, "")
+		assert.Equal(t, "83.123456", priced.Mid.String())
+		assert.Equal(t, "82.707838", priced.Bid.String())
+		assert.Equal(t, "83.539074", priced.Ask.String())
+		assert.Equal(t, 50, priced.SpreadBps)
+	})
+
+	t.Run("Rejects invalid spreads", func(t *testing.T) {
+		_, err := pricing.NewEngine(-1, nil, nil)
+		assert.Error(t, err)
+		_, err = pricing.NewEngine(0, nil, []pricing.PairRule{{From: "USD", To: "EUR", SpreadBps: 10000}})
+		assert.Error(t, err)
+		_, err = pricing.NewEngine(0, nil, []pricing.PairRule{{From: "USD", To: "EUR"}, {From: "usd", To: "eur"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestPricedConversion(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	mid, _ := domain.NewMoneyFromString("83.123456", 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: mid, Amount: mid}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour), service.WithPricing(newTestPricingEngine(t)))
+	defer server.Close()
+
+	t.Run("Convert shows customer rate, mid rate and margin", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/api/v2/convert", "application/json",
+			strings.NewReader(`{"from": "USD", "to": "INR", "amount": {"value": "100"}}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result domain.ConversionResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "82.707838", result.Rate.String())
+		assert.Equal(t, "83.123456", result.MidRate.String())
+		assert.Equal(t, 50, result.SpreadBps)
+		assert.Equal(t, "8270.78", result.Result.String())
+		assert.Equal(t, "41.565600", result.Margin.String())
+		assert.Equal(t, "INR", result.MarginCurrency)
+	})
+
+	t.Run("Rates expose bid, ask and mid", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/rates/USD/INR?segment=retail&amount=500")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Rate domain.Money `json:"rate"`
+			domain.PricedRate
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, result.Bid, result.Rate)
+		assert.Equal(t, "83.123456", result.Mid.String())
+		assert.Equal(t, 75, result.SpreadBps)
+		assert.Equal(t, -1, result.Bid.Cmp(result.Mid))
+		assert.Equal(t, 1, result.Ask.Cmp(result.Mid))
+	})
+
+	t.Run("Converts at the rate it showed", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/rates/USD/INR?segment=retail&amount=500")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var shown struct {
+			Rate domain.Money `json:"rate"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&shown))
+
+		body := fmt.Sprintf(`{"from": "USD", "to": "INR", "amount": {"value": "500"}, "segment": "retail", "expected_rate": {"value": %q}}`, shown.Rate.String())
+		resp, err = http.Post(server.URL+"/api/v2/convert", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result domain.ConversionResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, shown.Rate.String(), result.Rate.String())
+	})
+
+	t.Run("Rejects unknown segments", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v2/rates/USD/INR?segment=wholesale")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
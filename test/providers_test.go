@@ -0,0 +1,115 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// hangingAPI never answers until its context ends, like a provider that
+// accepted the connection and stalled.
+type hangingAPI struct{}
+
+func (hangingAPI) Convert(ctx context.Context, req domain.ExchangeRate) (domain.ExchangeRateResponse, error) {
+	<-ctx.Done()
+	return domain.ExchangeRateResponse{}, ctx.Err()
+}
+
+func (hangingAPI) GetRate(ctx context.Context, from, to string, date time.Time) (domain.Money, error) {
+	<-ctx.Done()
+	return domain.Money{}, ctx.Err()
+}
+
+func TestProviderRegistry(t *testing.T) {
+	primaryRate, _ := domain.NewMoneyFromString("0.92", 6)
+	backupRate, _ := domain.NewMoneyFromString("0.93", 6)
+	req := domain.ExchangeRate{From: "USD", To: "EUR", Rate: domain.NewMoney(1, 6)}
+
+	t.Run("Fails over and prefers the primary once it recovers", func(t *testing.T) {
+		primary, backup := &MockExchangeRateAPI{}, &MockExchangeRateAPI{}
+		primary.On("Convert", mock.Anything, mock.Anything).
+			Return(domain.ExchangeRateResponse{}, errors.New("503 service unavailable")).Once()
+		primary.On("Convert", mock.Anything, mock.Anything).
+			Return(domain.ExchangeRateResponse{Success: true, Rate: primaryRate}, nil)
+		backup.On("Convert", mock.Anything, mock.Anything).
+			Return(domain.ExchangeRateResponse{Success: true, Rate: backupRate}, nil)
+
+		registry, err := external.NewRegistry(log.NewNopLogger(), 50*time.Millisecond,
+			external.Provider{Name: "primary", API: primary},
+			external.Provider{Name: "backup", API: backup},
+		)
+		assert.NoError(t, err)
+
+		resp, err := registry.Convert(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.930000", resp.Rate.String())
+		health := registry.Health()
+		assert.False(t, health[0].Healthy)
+		assert.Equal(t, 1, health[0].ConsecutiveFailures)
+		assert.Contains(t, health[0].LastError, "503")
+		assert.True(t, health[1].Healthy)
+
+		// Cooling down: the primary is skipped.
+		resp, err = registry.Convert(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.930000", resp.Rate.String())
+		primary.AssertNumberOfCalls(t, "Convert", 1)
+
+		time.Sleep(60 * time.Millisecond)
+		resp, err = registry.Convert(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.920000", resp.Rate.String())
+		assert.True(t, registry.Health()[0].Healthy)
+		backup.AssertNumberOfCalls(t, "Convert", 2)
+	})
+
+	t.Run("Fails over when a provider times out", func(t *testing.T) {
+		backup := &MockExchangeRateAPI{}
+		backup.On("GetRate", mock.Anything, "USD", "EUR", mock.Anything).Return(backupRate, nil)
+
+		registry, err := external.NewRegistry(log.NewNopLogger(), time.Minute,
+			external.Provider{Name: "slow", API: hangingAPI{}, Timeout: 20 * time.Millisecond},
+			external.Provider{Name: "backup", API: backup},
+		)
+		assert.NoError(t, err)
+
+		rate, err := registry.GetRate(context.Background(), "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.930000", rate.String())
+		assert.Contains(t, registry.Health()[0].LastError, "deadline exceeded")
+	})
+
+	t.Run("Reports every failure when all providers are down", func(t *testing.T) {
+		a, b := &MockExchangeRateAPI{}, &MockExchangeRateAPI{}
+		a.On("Convert", mock.Anything, mock.Anything).Return(domain.ExchangeRateResponse{}, errors.New("boom"))
+		b.On("Convert", mock.Anything, mock.Anything).Return(domain.ExchangeRateResponse{Success: false}, nil)
+
+		registry, err := external.NewRegistry(log.NewNopLogger(), time.Minute,
+			external.Provider{Name: "a", API: a}, external.Provider{Name: "b", API: b})
+		assert.NoError(t, err)
+
+		_, err = registry.Convert(context.Background(), req)
+		assert.ErrorContains(t, err, "a: boom")
+		assert.ErrorContains(t, err, "b: conversion failed")
+
+		// Both cooling down: they are still tried rather than failing outright.
+		_, err = registry.Convert(context.Background(), req)
+		assert.Error(t, err)
+		a.AssertNumberOfCalls(t, "Convert", 2)
+	})
+
+	t.Run("Rejects invalid provider lists", func(t *testing.T) {
+		_, err := external.NewRegistry(log.NewNopLogger(), 0)
+		assert.Error(t, err)
+		_, err = external.NewRegistry(log.NewNopLogger(), 0,
+			external.Provider{Name: "a", API: &MockExchangeRateAPI{}}, external.Provider{Name: "a", API: &MockExchangeRateAPI{}})
+		assert.Error(t, err)
+	})
+}
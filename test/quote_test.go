@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	service "github.com/MdSadiqMd/Exchange-Rate-Service/internal/services"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuotes(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate := domain.NewMoney(0.9, 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour), service.WithQuoteTTL(200*time.Millisecond))
+	defer server.Close()
+
+	createQuote := func(t *testing.T, body string) domain.Quote {
+		resp, err := http.Post(server.URL+"/api/v2/quotes", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result domain.QuoteResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Quote
+	}
+	execute := func(t *testing.T, id string, v interface{}) *http.Response {
+		resp, err := http.Post(server.URL+"/api/v2/quotes/"+id+"/execute", "application/json", nil)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		return resp
+	}
+
+	t.Run("Executes once at the locked rate", func(t *testing.T) {
+		quote := createQuote(t, `{"from": "USD", "to": "EUR", "amount": {"value": "100"}}`)
+		assert.Len(t, quote.ID, 32)
+		assert.Equal(t, "0.900000", quote.Rate.String())
+		assert.Equal(t, "90.00", quote.Result.String())
+		assert.Equal(t, 200*time.Millisecond, quote.ExpiresAt.Sub(quote.CreatedAt))
+
+		var result domain.QuoteExecutionResponse
+		resp := execute(t, quote.ID, &result)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, quote.ID, result.QuoteID)
+		assert.Equal(t, quote.Rate.String(), result.Conversion.Rate.String())
+		assert.Equal(t, quote.Result.String(), result.Conversion.Result.String())
+
+		var again map[string]interface{}
+		resp = execute(t, quote.ID, &again)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeQuoteNotFound, again["code"])
+	})
+
+	t.Run("Rejects expired quotes", func(t *testing.T) {
+		quote := createQuote(t, `{"from": "USD", "to": "JPY", "amount": {"value": "5"}}`)
+		time.Sleep(250 * time.Millisecond)
+
+		for i := 0; i < 2; i++ {
+			var result map[string]interface{}
+			resp := execute(t, quote.ID, &result)
+			assert.Equal(t, http.StatusGone, resp.StatusCode)
+			assert.Equal(t, domain.ErrCodeQuoteExpired, result["code"])
+		}
+	})
+
+	t.Run("Refuses to quote past dates", func(t *testing.T) {
+		date := time.Now().UTC().AddDate(0, 0, -3).Format("2006-01-02")
+		resp, err := http.Post(server.URL+"/api/v2/quotes", "application/json",
+			strings.NewReader(`{"from": "USD", "to": "EUR", "amount": {"value": "1"}, "date": "`+date+`"}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
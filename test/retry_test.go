@@ -0,0 +1,158 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyUpstream answers with the given statuses in turn, then with a
+// successful conversion. It counts the requests it received.
+func flakyUpstream(t *testing.T, hits *atomic.Int32, statuses ...int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{"success": true, "result": 0.92, "info": {"rate": 0.92}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func fastRetries(attempts int) external.RetryPolicy {
+	policy := external.DefaultRetryPolicy()
+	policy.MaxAttempts = attempts
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+func TestClientRetry(t *testing.T) {
+	req := domain.ExchangeRate{From: "USD", To: "EUR", Rate: domain.NewMoney(1, 6)}
+
+	t.Run("Retries transient statuses until success", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := flakyUpstream(t, &hits, http.StatusBadGateway, http.StatusServiceUnavailable)
+
+		var mu sync.Mutex
+		var attempts []interface{}
+		logger := log.LoggerFunc(func(keyvals ...interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for i := 0; i+1 < len(keyvals); i += 2 {
+				if keyvals[i] == "msg" && keyvals[i+1] == "calling rate provider" {
+					attempts = append(attempts, keyvals[i+1])
+				}
+			}
+			return nil
+		})
+
+		client := external.NewClient(upstream.URL, "key", time.Second,
+			external.WithRetryPolicy(fastRetries(3)), external.WithLogger(logger))
+		resp, err := client.Convert(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.920000", resp.Rate.String())
+		assert.Equal(t, int32(3), hits.Load())
+		assert.Len(t, attempts, 3)
+	})
+
+	t.Run("Gives up after max attempts", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := flakyUpstream(t, &hits, 503, 503, 503, 503)
+		client := external.NewClient(upstream.URL, "key", time.Second, external.WithRetryPolicy(fastRetries(3)))
+
+		_, err := client.Convert(context.Background(), req)
+		var status *external.StatusError
+		assert.True(t, errors.As(err, &status))
+		assert.Equal(t, 503, status.Code)
+		assert.Equal(t, int32(3), hits.Load())
+	})
+
+	t.Run("Does not retry other statuses or error classes", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := flakyUpstream(t, &hits, http.StatusBadRequest)
+		client := external.NewClient(upstream.URL, "key", time.Second, external.WithRetryPolicy(fastRetries(3)))
+		_, err := client.Convert(context.Background(), req)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), hits.Load())
+
+		hits.Store(0)
+		policy := fastRetries(3)
+		policy.RetryOn = []external.ErrorClass{external.ClassTimeout}
+		upstream = flakyUpstream(t, &hits, http.StatusBadGateway)
+		client = external.NewClient(upstream.URL, "key", time.Second, external.WithRetryPolicy(policy))
+		_, err = client.Convert(context.Background(), req)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("Retries attempts that time out", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits.Add(1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.Write([]byte(`{"success": true, "result": 0.92, "info": {"rate": 0.92}}`))
+		}))
+		defer upstream.Close()
+
+		client := external.NewClient(upstream.URL, "key", 50*time.Millisecond, external.WithRetryPolicy(fastRetries(2)))
+		resp, err := client.Convert(context.Background(), req)
+		assert.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("Never waits past the context deadline", func(t *testing.T) {
+		var hits atomic.Int32
+		upstream := flakyUpstream(t, &hits, 503, 503, 503)
+		policy := fastRetries(3)
+		policy.BaseDelay, policy.MaxDelay = time.Second, time.Second
+		client := external.NewClient(upstream.URL, "key", time.Second, external.WithRetryPolicy(policy))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := client.Convert(ctx, req)
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("Keeps the API key out of network errors", func(t *testing.T) {
+		upstream := httptest.NewServer(http.NotFoundHandler())
+		upstream.Close()
+		client := external.NewClient(upstream.URL, "s3cret", time.Second, external.WithRetryPolicy(fastRetries(2)))
+		_, err := client.Convert(context.Background(), req)
+		assert.Error(t, err)
+		assert.NotContains(t, err.Error(), "s3cret")
+	})
+
+	t.Run("Validates policies", func(t *testing.T) {
+		assert.NoError(t, external.DefaultRetryPolicy().Validate())
+		policy := external.DefaultRetryPolicy()
+		policy.MaxAttempts = 0
+		assert.Error(t, policy.Validate())
+		policy = external.DefaultRetryPolicy()
+		policy.Jitter = 1.5
+		assert.Error(t, policy.Validate())
+		policy = external.DefaultRetryPolicy()
+		policy.BaseDelay = time.Minute
+		assert.Error(t, policy.Validate())
+		_, err := external.ParseErrorClass("dns")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReverseConversion(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate, err := domain.NewMoneyFromString("83.123456", 6)
+	assert.NoError(t, err)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+	defer server.Close()
+
+	post := func(t *testing.T, path, body string, v interface{}) *http.Response {
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		return resp
+	}
+
+	t.Run("Charges enough to cover the target", func(t *testing.T) {
+		var result domain.ConversionResponse
+		resp := post(t, "/api/v2/convert", `{"from": "USD", "to": "INR", "amount": {"value": "10000"}, "fixed_side": "to", "rounding": "floor"}`, &result)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, domain.FixedTo, result.FixedSide)
+		assert.Equal(t, domain.RoundCeiling, result.Rounding)
+		assert.Equal(t, "83.123456", result.Rate.String())
+		assert.Equal(t, "120.302987", result.UnroundedResult.String())
+		assert.Equal(t, "120.31", result.Result.String())
+
+		if assert.NotNil(t, result.RoundTripAmount) {
+			assert.Equal(t, "10000.582991", result.RoundTripAmount.String())
+			target, _ := domain.NewMoneyFromString("10000", 2)
+			assert.GreaterOrEqual(t, result.RoundTripAmount.Cmp(target), 0)
+		}
+	})
+
+	t.Run("Forward conversion stays the default", func(t *testing.T) {
+		var result domain.ConversionResponse
+		post(t, "/api/v2/convert", `{"from": "USD", "to": "INR", "amount": {"value": "120.31"}}`, &result)
+		assert.Equal(t, domain.FixedFrom, result.FixedSide)
+		assert.Nil(t, result.RoundTripAmount)
+		assert.Equal(t, "10000.58", result.Result.String())
+	})
+
+	t.Run("Quotes keep the fixed side through execution", func(t *testing.T) {
+		var quote domain.QuoteResponse
+		post(t, "/api/v2/quotes", `{"from": "USD", "to": "INR", "amount": {"value": "10000"}, "fixed_side": "to"}`, &quote)
+		assert.Equal(t, "120.31", quote.Quote.Result.String())
+
+		var executed domain.QuoteExecutionResponse
+		post(t, "/api/v2/quotes/"+quote.Quote.ID+"/execute", ``, &executed)
+		assert.Equal(t, "120.31", executed.Conversion.Result.String())
+		assert.Equal(t, domain.FixedTo, executed.Conversion.FixedSide)
+	})
+
+	t.Run("Rejects unknown sides", func(t *testing.T) {
+		var result map[string]interface{}
+		resp := post(t, "/api/v2/convert", `{"from": "USD", "to": "INR", "amount": {"value": "1"}, "fixed_side": "both"}`, &result)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSlippageProtection(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate := domain.NewMoney(0.9, 6)
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+	defer server.Close()
+
+	convert := func(t *testing.T, body string) (*http.Response, map[string]interface{}) {
+		resp, err := http.Post(server.URL+"/api/v2/convert", "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+
+	t.Run("Converts within tolerance", func(t *testing.T) {
+		resp, _ := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "expected_rate": {"value": "0.905"}, "max_slippage_bps": 100}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, _ = convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "expected_rate": {"value": "0.9"}}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Rejects a moved rate with the current rate", func(t *testing.T) {
+		resp, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "expected_rate": {"value": "0.91"}, "max_slippage_bps": 100}`)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeSlippageExceeded, result["code"])
+
+		details := result["details"].(map[string]interface{})
+		assert.Equal(t, "0.900000", details["current_rate"])
+		assert.Equal(t, "109.90", details["slippage_bps"])
+		assert.EqualValues(t, 100, details["max_slippage_bps"])
+	})
+
+	t.Run("Zero tolerance requires the exact rate", func(t *testing.T) {
+		resp, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "expected_rate": {"value": "0.900001"}}`)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeSlippageExceeded, result["code"])
+	})
+
+	t.Run("Tolerance needs an expected rate", func(t *testing.T) {
+		resp, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "max_slippage_bps": 50}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeInvalidRequest, result["code"])
+
+		resp, _ = convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "expected_rate": {"value": "0.9"}, "max_slippage_bps": 20000}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Bounds the expected rate like an amount", func(t *testing.T) {
+		resp, result := convert(t, `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "expected_rate": {"amount": 1, "scale": 2000000}}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, domain.ErrCodeInvalidRequest, result["code"])
+		assert.Equal(t, "expected_rate", result["details"].(map[string]interface{})["field"])
+	})
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/external"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Serves CSV rows by date", func(t *testing.T) {
+		provider, err := external.NewStaticProvider("testdata/static-rates.csv", 0, time.Minute)
+		assert.NoError(t, err)
+
+		rate, err := provider.GetRate(ctx, "USD", "EUR", fixtureDate("2025-08-20"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0.858500", rate.String())
+
+		resp, err := provider.Convert(ctx, domain.ExchangeRate{From: "usd", To: "eur", Rate: domain.NewMoney(100, 2)})
+		assert.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Equal(t, "0.861500", resp.Rate.String())
+		assert.Equal(t, "86.150000", resp.Amount.String())
+		assert.Equal(t, fixtureDate("2025-08-21"), resp.Timestamp)
+	})
+
+	t.Run("Derives the inverse of a listed pair", func(t *testing.T) {
+		provider, err := external.NewStaticProvider("testdata/static-rates.csv", 0, time.Minute)
+		assert.NoError(t, err)
+		rate, err := provider.GetRate(ctx, "USD", "GBP", fixtureDate("2025-08-20"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0.741840", rate.String())
+	})
+
+	t.Run("Falls back to the nearest previous date when configured", func(t *testing.T) {
+		strict, err := external.NewStaticProvider("testdata/static-rates.csv", 0, time.Minute)
+		assert.NoError(t, err)
+		_, err = strict.GetRate(ctx, "USD", "EUR", fixtureDate("2025-08-19"))
+		assert.True(t, errors.Is(err, domain.ErrRateNotFound))
+
+		lenient, err := external.NewStaticProvider("testdata/static-rates.csv", 3, time.Minute)
+		assert.NoError(t, err)
+		resp, err := lenient.Convert(ctx, domain.ExchangeRate{From: "USD", To: "EUR", Rate: domain.NewMoney(1, 6), Date: fixtureDate("2025-08-19")})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.857500", resp.Rate.String())
+		assert.Equal(t, fixtureDate("2025-08-18"), resp.Timestamp)
+
+		_, err = lenient.GetRate(ctx, "USD", "EUR", fixtureDate("2025-08-01"))
+		assert.True(t, errors.Is(err, domain.ErrRateNotFound))
+		_, err = lenient.GetRate(ctx, "USD", "INR", fixtureDate("2025-08-25"))
+		assert.True(t, errors.Is(err, domain.ErrRateNotFound))
+	})
+
+	t.Run("Reads JSON with string or number rates", func(t *testing.T) {
+		provider, err := external.NewStaticProvider("testdata/static-rates.json", 0, time.Minute)
+		assert.NoError(t, err)
+		rate, err := provider.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.861500", rate.String())
+		rate, err = provider.GetRate(ctx, "USD", "JPY", fixtureDate("2025-08-21"))
+		assert.NoError(t, err)
+		assert.Equal(t, "147.500000", rate.String())
+	})
+
+	t.Run("Rejects malformed files", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, content := range map[string]string{
+			"no-rate.csv":   "date,from,to\n2025-08-21,USD,EUR\n",
+			"bad-date.csv":  "date,from,to,rate\n21/08/2025,USD,EUR,0.86\n",
+			"bad-rate.json": `[{"date": "2025-08-21", "from": "USD", "to": "EUR", "rate": "-1"}]`,
+		} {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+			_, err := external.NewStaticProvider(path, 0, time.Minute)
+			assert.Error(t, err, name)
+		}
+		_, err := external.NewStaticProvider("testdata/static-rates.csv", -1, time.Minute)
+		assert.Error(t, err)
+	})
+
+	t.Run("Reloads the file when it changes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rates.csv")
+		assert.NoError(t, os.WriteFile(path, []byte("date,from,to,rate\n2025-08-21,USD,EUR,0.86\n"), 0o644))
+		provider, err := external.NewStaticProvider(path, 0, time.Millisecond)
+		assert.NoError(t, err)
+
+		rate, err := provider.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.860000", rate.String())
+
+		assert.NoError(t, os.WriteFile(path, []byte("date,from,to,rate\n2025-08-22,USD,EUR,0.8712\n"), 0o644))
+		time.Sleep(5 * time.Millisecond)
+		rate, err = provider.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.871200", rate.String())
+
+		// A broken rewrite keeps the last good rates.
+		assert.NoError(t, os.WriteFile(path, []byte("date,from,to,rate\n2025-08-23,USD,EUR,"), 0o644))
+		time.Sleep(5 * time.Millisecond)
+		rate, err = provider.GetRate(ctx, "USD", "EUR", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.871200", rate.String())
+	})
+}
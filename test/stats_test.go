@@ -0,0 +1,93 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateStats(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -20)
+	rates := map[int]string{0: "1.0", 1: "1.2", 3: "1.4"}
+
+	mockAPI := &MockExchangeRateAPI{}
+	for offset := 0; offset < 4; offset++ {
+		date := start.AddDate(0, 0, offset)
+		call := mockAPI.On("Convert", mock.Anything, mock.MatchedBy(func(req domain.ExchangeRate) bool {
+			return req.Date.Equal(date)
+		}))
+		if value, ok := rates[offset]; ok {
+			rate, err := domain.NewMoneyFromString(value, 6)
+			assert.NoError(t, err)
+			call.Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate}, nil)
+		} else {
+			call.Return(domain.ExchangeRateResponse{Success: false}, errors.New("no data for date"))
+		}
+	}
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+	defer server.Close()
+
+	get := func(t *testing.T, end time.Time) (*http.Response, domain.RateStatsResponse) {
+		url := server.URL + "/api/v2/rates/USD/EUR/stats?start=" + start.Format("2006-01-02") + "&end=" + end.Format("2006-01-02")
+		resp, err := http.Get(url)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result domain.RateStatsResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+
+	t.Run("Summarises the priced points", func(t *testing.T) {
+		resp, result := get(t, start.AddDate(0, 0, 3))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, result.Success)
+		assert.Equal(t, 3, result.Points)
+		assert.Equal(t, 1, result.Gaps)
+		assert.Equal(t, start.Format("2006-01-02"), result.StartRate.Date)
+		assert.Equal(t, start.AddDate(0, 0, 3).Format("2006-01-02"), result.EndRate.Date)
+		assert.Equal(t, "0.400000", result.Change.String())
+		assert.Equal(t, "40.000000", result.ChangePercent.String())
+		assert.Equal(t, "1.000000", result.Min.String())
+		assert.Equal(t, "1.400000", result.Max.String())
+		assert.Equal(t, "1.200000", result.Mean.String())
+		assert.Equal(t, "0.163299", result.StdDev.String())
+	})
+
+	t.Run("A flat series has zero deviation", func(t *testing.T) {
+		_, result := get(t, start)
+		assert.Equal(t, 1, result.Points)
+		assert.True(t, result.Change.IsZero())
+		assert.True(t, result.StdDev.IsZero())
+	})
+}
+
+func TestMoneyQuoAndSqrt(t *testing.T) {
+	one := domain.NewMoney(1, 0)
+	three := domain.NewMoney(3, 0)
+	assert.Equal(t, "0.333333333333333333", one.Quo(three, 18).String())
+	assert.Equal(t, "0.67", one.Add(one).Quo(three, 2, domain.RoundHalfEven).String())
+
+	two := domain.NewMoney(2, 0)
+	assert.Equal(t, "1.414213", two.Sqrt(6).String())
+	assert.Equal(t, "1.414214", two.Sqrt(6, domain.RoundCeiling).String())
+	assert.Equal(t, "1.41421356237", two.Sqrt(11, domain.RoundHalfEven).String())
+
+	exact, err := domain.NewMoneyFromString("2.25", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.5", exact.Sqrt(1, domain.RoundCeiling).String())
+	assert.Equal(t, "2", exact.Sqrt(0, domain.RoundHalfEven).String())
+	assert.True(t, exact.Neg().Sqrt(4).IsZero())
+
+	product := domain.NewMoney(1.5, 6).MultiplyExact(domain.NewMoney(1.5, 6))
+	assert.Equal(t, 12, product.Scale)
+	assert.Equal(t, "2.250000000000", product.String())
+}
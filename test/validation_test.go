@@ -0,0 +1,218 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MdSadiqMd/Exchange-Rate-Service/internal/domain"
+	"github.com/MdSadiqMd/Exchange-Rate-Service/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestValidation(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	rate := domain.NewMoney(0.92, 6)
+	mockAPI.On("Convert", mock.Anything, mock.MatchedBy(func(req domain.ExchangeRate) bool {
+		return req.From == "USD" && req.To == "EUR"
+	})).Return(domain.ExchangeRateResponse{Success: true, Rate: rate, Amount: rate, Timestamp: time.Now()}, nil)
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+		errorCode  string
+	}{
+		{
+			name:       "Convert normalizes case",
+			method:     http.MethodPost,
+			path:       "/api/v2/convert",
+			body:       `{"from": "usd", "to": "Eur", "amount": {"value": "10"}}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Convert rejects unknown currency",
+			method:     http.MethodPost,
+			path:       "/api/v2/convert",
+			body:       `{"from": "USDD", "to": "EUR", "amount": {"value": "10"}}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeUnsupportedCurrency,
+		},
+		{
+			name:       "Convert rejects disabled currency",
+			method:     http.MethodPost,
+			path:       "/api/v2/convert",
+			body:       `{"from": "USD", "to": "KWD", "amount": {"value": "10"}}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeUnsupportedCurrency,
+		},
+		{
+			name:       "Convert rejects identical pair",
+			method:     http.MethodPost,
+			path:       "/api/v2/convert",
+			body:       `{"from": "usd", "to": "USD", "amount": {"value": "10"}}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeSameCurrency,
+		},
+		{
+			name:       "Rates normalizes case",
+			method:     http.MethodGet,
+			path:       "/api/v2/rates/usd/eur",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Rates rejects unknown currency",
+			method:     http.MethodGet,
+			path:       "/api/v2/rates/USDD/EUR",
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeUnsupportedCurrency,
+		},
+		{
+			name:       "Rates rejects identical pair",
+			method:     http.MethodGet,
+			path:       "/api/v2/rates/EUR/eur",
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeSameCurrency,
+		},
+		{
+			name:       "Convert rejects unnecessary rounding",
+			method:     http.MethodPost,
+			path:       "/api/v2/convert",
+			body:       `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "rounding": "unnecessary"}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeInvalidRequest,
+		},
+		{
+			name:       "Multi convert rejects unnecessary rounding",
+			method:     http.MethodPost,
+			path:       "/api/v2/convert/multi",
+			body:       `{"from": "USD", "to": ["EUR"], "amount": {"value": "10"}, "rounding": "unnecessary"}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeInvalidRequest,
+		},
+		{
+			name:       "Average rejects unnecessary rounding",
+			method:     http.MethodGet,
+			path:       "/api/v2/rates/USD/EUR/average?period=2025-03&rounding=unnecessary",
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+			if tt.errorCode == "" {
+				return
+			}
+
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.errorCode, body["code"])
+		})
+	}
+}
+
+func TestErrorModel(t *testing.T) {
+	mockAPI := &MockExchangeRateAPI{}
+	mockAPI.On("Convert", mock.Anything, mock.Anything).
+		Return(domain.ExchangeRateResponse{}, errors.New("dial tcp: connection refused"))
+
+	server := newMockServer(mockAPI, cache.NewMemoryCache(time.Hour))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		errorCode  string
+	}{
+		{
+			name:       "Upstream outage",
+			body:       `{"from": "USD", "to": "EUR", "amount": {"value": "10"}}`,
+			statusCode: http.StatusBadGateway,
+			errorCode:  domain.ErrCodeUpstreamUnavailable,
+		},
+		{
+			name:       "Date too old",
+			body:       `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "date": "2001-01-01"}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeDateOutOfRange,
+		},
+		{
+			name:       "Malformed date",
+			body:       `{"from": "USD", "to": "EUR", "amount": {"value": "10"}, "date": "01/01/2001"}`,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeInvalidDate,
+		},
+		{
+			name:       "Malformed JSON",
+			body:       `{"from": `,
+			statusCode: http.StatusBadRequest,
+			errorCode:  domain.ErrCodeInvalidRequest,
+		},
+		{
+			name:       "Amount overflow",
+			body:       `{"from": "USD", "to": "EUR", "amount": {"value": "1e60"}}`,
+			statusCode: http.StatusUnprocessableEntity,
+			errorCode:  domain.ErrCodeAmountOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/v2/convert", "application/json", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			var body struct {
+				Code      string                 `json:"code"`
+				Message   string                 `json:"message"`
+				Details   map[string]interface{} `json:"details"`
+				RequestID string                 `json:"request_id"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+			assert.Equal(t, tt.errorCode, body.Code)
+			assert.NotEmpty(t, body.Message)
+			assert.NotEmpty(t, body.RequestID)
+		})
+	}
+
+	t.Run("Keeps upstream detail out of the response", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/api/v2/convert", "application/json",
+			strings.NewReader(`{"from": "USD", "to": "EUR", "amount": {"value": "10"}}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		raw, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(raw), "connection refused")
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(raw, &body))
+		assert.NotContains(t, body, "error")
+	})
+
+	t.Run("Kinds match with errors.Is", func(t *testing.T) {
+		err := domain.NewError(domain.ErrRateNotFound, "no EUR rate for 2024-01-01", nil)
+		assert.ErrorIs(t, err, domain.ErrRateNotFound)
+		assert.NotErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	})
+}